@@ -1,28 +1,50 @@
 // Package cli provides a command line argument parser and task executer
 // which you can use to build simple command line interfaces for your application
+// Declare the flags and the named positional args of the Commands, and use WithRun to access their typed values
 package cli
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 // Command represents a terminal command
+// The help of the Command (app help items, app items --help) is generated from the tree of the Commands
+// with their descriptions, examples, aliases, flags and args.
+// If the Command (or its parents with persistent flags) declares flags or args, the flags are parsed
+// and the Task receives the positional arguments, otherwise it receives the raw arguments.
 type Command struct {
-	Name        string
-	Aliases     []string
-	Task        func(args []string) error
-	SubCommands []*Command
+	Name            string
+	Aliases         []string
+	Task            func(args []string) error
+	ContextTask     func(ctx context.Context, args []string) error
+	Run             func(inv *Invocation) error
+	PreRun          func(inv *Invocation) error
+	PostRun         func(inv *Invocation) error
+	Middlewares     []Middleware
+	SubCommands     []*Command
+	Flags           []*Flag
+	PersistentFlags []*Flag
+	Args            []*Arg
+	Short           string
+	Long            string
+	Examples        []string
+	Timeout         time.Duration
+	PrefixMatching  bool
+
+	output io.Writer
 }
 
 // NewCommand creates a new Command with the given name
+// A Command without Task, ContextTask and Run does nothing, but fails on an invalid subcommand.
 func NewCommand(name string) *Command {
 	return &Command{
 		Name: name,
-		Task: func(args []string) error {
-			return nil
-		},
 	}
 }
 
@@ -38,6 +60,45 @@ func (c *Command) WithTask(task func(args []string) error) *Command {
 	return c
 }
 
+// WithContextTask adds the supplied task to the Command, which receives the context of the execution
+// and is executed instead of the Task
+func (c *Command) WithContextTask(task func(ctx context.Context, args []string) error) *Command {
+	c.ContextTask = task
+	return c
+}
+
+// WithTimeout sets the timeout of the execution of the Command and its SubCommands (unless they set their own),
+// the context of the execution is cancelled after the timeout
+func (c *Command) WithTimeout(timeout time.Duration) *Command {
+	c.Timeout = timeout
+	return c
+}
+
+// WithRun adds the supplied run function to the Command, which is executed instead of the Task
+// with the parsed flags and args
+func (c *Command) WithRun(run func(inv *Invocation) error) *Command {
+	c.Run = run
+	return c
+}
+
+// WithFlags adds the supplied Flags to the Command
+func (c *Command) WithFlags(flags ...*Flag) *Command {
+	c.Flags = append(c.Flags, flags...)
+	return c
+}
+
+// WithPersistentFlags adds the supplied Flags to the Command, which are inherited by its SubCommands
+func (c *Command) WithPersistentFlags(flags ...*Flag) *Command {
+	c.PersistentFlags = append(c.PersistentFlags, flags...)
+	return c
+}
+
+// WithArgs adds the supplied named positional Args to the Command
+func (c *Command) WithArgs(args ...*Arg) *Command {
+	c.Args = append(c.Args, args...)
+	return c
+}
+
 // WithSubCommands adds the supplied list of Commands to the Command as SubCommands
 func (c *Command) WithSubCommands(subCommands ...*Command) *Command {
 	c.SubCommands = append(c.SubCommands, subCommands...)
@@ -45,17 +106,105 @@ func (c *Command) WithSubCommands(subCommands ...*Command) *Command {
 }
 
 // Execute starts the recursive execution of the CLI
+// The flags must follow the path of the subcommands, like app items get --verbose 2
 func (c *Command) Execute(args []string) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return c.execute(context.Background(), nil, args)
+}
+
+// ExecuteContext starts the recursive execution of the CLI with the context, which is cancelled on the first
+// SIGINT or SIGTERM (the next one terminates the process). The context is passed to the ContextTask and
+// the Run functions, the error carries the exit code for os.Exit, see ExitCode.
+func (c *Command) ExecuteContext(ctx context.Context, args []string) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	ctx, stop := notifyContext(ctx)
+	defer stop()
+	return c.execute(ctx, nil, args)
+}
+
+// execute executes the matching subcommand, or the Command with the parents of the Command
+func (c *Command) execute(ctx context.Context, parents []*Command, args []string) error {
 	// If there is 1 or more args, check if the first arg matches with any subcommand
 	if len(args) > 0 {
-		for _, subCommand := range c.SubCommands {
-			// Check for both the Command's Name and the Aliases too
-			if subCommand.Name == args[0] || match(args[0], subCommand.Aliases) {
-				return subCommand.Execute(args[1:])
-			}
+		subCommand, err := c.lookup(parents, args[0])
+		if err != nil {
+			return err
+		}
+		if subCommand != nil {
+			return subCommand.execute(ctx, append(parents, c), args[1:])
+		}
+		// The help subcommand prints the help of the Command at the path, unless a "help" SubCommand is declared
+		if args[0] == "help" && len(c.SubCommands) > 0 {
+			target, targetParents := c.helpTarget(parents, args[1:])
+			fmt.Fprint(target.out(targetParents), target.help(targetParents))
+			return nil
+		}
+		// Nothing would handle the argument, so it is an invalid subcommand
+		if len(c.SubCommands) > 0 && c.Task == nil && c.ContextTask == nil && c.Run == nil &&
+			!strings.HasPrefix(args[0], "-") {
+			return &ExitError{Code: ExitCodeUsage, Err: c.invalidCommandError(args[0])}
 		}
 	}
+	if c.helpRequested(parents, args) {
+		fmt.Fprint(c.out(parents), c.help(parents))
+		return nil
+	}
+	ctx, cancel := c.withTimeout(ctx, parents)
+	defer cancel()
+	return exitError(ctx, c.run(ctx, parents, args))
+}
+
+// run executes the Run function or the Task of the Command with the context, wrapped by the hooks
+// and the middlewares of the Command and its parents
+func (c *Command) run(ctx context.Context, parents []*Command, args []string) error {
+	inv, err := c.invocation(ctx, parents, args)
+	if err != nil {
+		return err
+	}
+	err = c.handler(parents)(inv)
+	// the invalid command of EndWithMessage gets the suggestions of the Command
+	var invalid *invalidCommand
+	if errors.As(err, &invalid) {
+		return &ExitError{Code: ExitCodeUsage, Err: c.invalidCommandError(invalid.name)}
+	}
+	return err
+}
+
+// invocation returns the Invocation of the Command with the parsed flags and args, or with the raw arguments
+// if the Command (and its parents) declare nothing and it has no Run function
+func (c *Command) invocation(ctx context.Context, parents []*Command, args []string) (*Invocation, error) {
 	// If no match were found just execute the Task
+	if !c.declaresArguments(parents) && c.Run == nil {
+		return &Invocation{
+			Command: c,
+			Path:    commandPath(parents, c),
+			Args:    args,
+			ctx:     ctx,
+			values:  map[string]interface{}{},
+			set:     map[string]bool{},
+			parents: parents,
+		}, nil
+	}
+	inv, err := c.parse(parents, args)
+	if err != nil {
+		return nil, err
+	}
+	inv.ctx = ctx
+	return inv, nil
+}
+
+// task executes the ContextTask, or the Task of the Command (if any)
+func (c *Command) task(ctx context.Context, args []string) error {
+	if c.ContextTask != nil {
+		return c.ContextTask(ctx, args)
+	}
+	if c.Task == nil {
+		return nil
+	}
 	return c.Task(args)
 }
 
@@ -71,12 +220,24 @@ func match(s string, clues []string) bool {
 
 // EndWithMessage returns a Task that prints out the supplied message and returns with an error
 // depending on the amount of remaining args (no command / invalid command)
+// Executed by a Command, the invalid command error suggests the similar SubCommands and its exit code
+// is ExitCodeUsage, like the error of EndWithHelp.
 func EndWithMessage(msg string) func(args []string) error {
 	return func(args []string) error {
 		fmt.Println(msg)
 		if len(args) > 0 {
-			return errors.Errorf("Invalid Command: %s", args[0])
+			return &invalidCommand{name: args[0]}
 		}
 		return errors.New("Command is missing")
 	}
 }
+
+// invalidCommand is the error of the invalid command of EndWithMessage
+type invalidCommand struct {
+	name string
+}
+
+// Error implements the error interface
+func (e *invalidCommand) Error() string {
+	return "Invalid Command: " + e.name
+}
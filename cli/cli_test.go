@@ -78,11 +78,13 @@ func (cts *CLITestSuite) TestWithSubCommand() {
 	subCmd3 := cts.getTestCommand("sub-command3").WithTask(testTask3).WithSubCommands(subCmd2)
 	cmd := cts.getTestCommand("test-command").WithSubCommands(subCmd1, subCmd3)
 
-	// sub-command2 cannot be called directly on root level, so the root command's task should be executed
-	cts.Nil(cmd.Execute([]string{"sub-command2"}), "The root command should not return any error")
+	// sub-command2 cannot be called directly on root level, and the root command has no task to handle it
+	err := cmd.Execute([]string{"sub-command2"})
+	cts.EqualError(err, "Invalid Command: sub-command2, did you mean sub-command1 or sub-command3?")
+	cts.Equal(ExitCodeUsage, ExitCode(err), "An invalid subcommand should be a usage error")
 	cts.Empty(testList, "The test list should be empty now")
 
-	err := cmd.Execute([]string{"sub-command1", "arg2"})
+	err = cmd.Execute([]string{"sub-command1", "arg2"})
 	cts.Error(err, "Test sub-command1 should have returned an error")
 	cts.Contains(err.Error(), "Test Error 1", "The error returned from sub-command1 should contain Test Error 1")
 	cts.Contains(testList, "arg2", "The test list should contain arg2 after sub-command1 is executed")
@@ -148,6 +150,29 @@ func (cts *CLITestSuite) TestEndWithMessage_InvalidCommand() {
 	cts.Contains(string(out), "Error 2", "The output should contain Error 1")
 }
 
+func (cts *CLITestSuite) TestEndWithMessage_Execute() {
+	// Switch os.Stdout with a custom writer
+	oldStdout := os.Stdout
+	reader, writer, pipeErr := os.Pipe()
+	cts.NoError(pipeErr, "OS Pipe should have been created")
+	os.Stdout = writer
+
+	cmd := NewCommand("app").WithTask(EndWithMessage("Usage: app items")).WithSubCommands(NewCommand("items"))
+	err := cmd.Execute([]string{"itms"})
+	cts.EqualError(err, "Invalid Command: itms, did you mean items?")
+	cts.Equal(ExitCodeUsage, ExitCode(err), "An invalid subcommand should be a usage error")
+
+	err = cmd.Execute([]string{})
+	cts.EqualError(err, "Command is missing")
+
+	// Read the custom writer's buffer and restore os.Stdout
+	writer.Close()
+	out, readErr := ioutil.ReadAll(reader)
+	cts.NoError(readErr, "The buffer should have been red")
+	os.Stdout = oldStdout
+	cts.Contains(string(out), "Usage: app items", "The output should contain the message")
+}
+
 // TestCLI runs the whole test suite
 func TestCLI(t *testing.T) {
 	suite.Run(t, new(CLITestSuite))
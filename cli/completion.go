@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Shells supported by the Completion
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// completionNode is a Command of the tree with the words completed after its path
+type completionNode struct {
+	// path is the canonical path of the Command without the root, like "items get"
+	path string
+	// command is the Command at the path
+	command *Command
+	// parents are the parents of the Command
+	parents []*Command
+}
+
+// completionNodes returns the Commands of the tree in depth-first order
+func (c *Command) completionNodes() []completionNode {
+	nodes := []completionNode{}
+	var walk func(command *Command, parents []*Command, path string)
+	walk = func(command *Command, parents []*Command, path string) {
+		nodes = append(nodes, completionNode{path: path, command: command, parents: parents})
+		for _, subCommand := range command.SubCommands {
+			walk(subCommand, append(append([]*Command{}, parents...), command), strings.TrimSpace(path+" "+subCommand.Name))
+		}
+	}
+	walk(c, nil, "")
+	return nodes
+}
+
+// flagWords returns the completed flags of the Command, like --limit -n
+func (n completionNode) flagWords() []string {
+	words := []string{}
+	for _, f := range n.command.flags(n.parents) {
+		words = append(words, "--"+f.Name)
+		if f.Short != "" {
+			words = append(words, "-"+f.Short)
+		}
+	}
+	return append(words, "--help")
+}
+
+// commandWords returns the completed SubCommands of the Command with their aliases
+func (n completionNode) commandWords() []string {
+	words := []string{}
+	for _, subCommand := range n.command.SubCommands {
+		words = append(words, subCommand.Name)
+		words = append(words, subCommand.Aliases...)
+	}
+	if len(n.command.SubCommands) > 0 && n.command.subCommand("help") == nil {
+		words = append(words, "help")
+	}
+	return words
+}
+
+// Completion returns the completion script of the Command tree for the shell (bash, zsh or fish).
+// The Name of the Command is the completed program, like source <(app completion bash).
+func (c *Command) Completion(shell string) (string, error) {
+	switch shell {
+	case ShellBash:
+		return c.bashCompletion(), nil
+	case ShellZsh:
+		return "#compdef " + c.Name + "\n\nautoload -U +X bashcompinit && bashcompinit\n\n" + c.bashCompletion(), nil
+	case ShellFish:
+		return c.fishCompletion(), nil
+	}
+	return "", errors.Errorf("Unsupported shell %s, use %s, %s or %s", shell, ShellBash, ShellZsh, ShellFish)
+}
+
+// NewCompletionCommand creates a new completion Command, which prints the completion script of the root
+// Command for the shell, like app completion bash
+func NewCompletionCommand(root *Command) *Command {
+	shells := []string{ShellBash, ShellZsh, ShellFish}
+	return NewCommand("completion").
+		WithShort("Print the shell completion script").
+		WithLong("Print the completion script of the "+root.Name+" command for the shell ("+strings.Join(shells, ", ")+").").
+		WithExamples(
+			"source <("+root.Name+" completion bash)",
+			root.Name+" completion zsh > \"${fpath[1]}/_"+root.Name+"\"",
+			root.Name+" completion fish > ~/.config/fish/completions/"+root.Name+".fish",
+		).
+		WithArgs(StringArg("shell", "The shell: "+strings.Join(shells, ", "))).
+		WithRun(func(inv *Invocation) error {
+			script, err := root.Completion(inv.String("shell"))
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(inv.Command.out(inv.parents), script)
+			return nil
+		})
+}
+
+// functionName returns the name of the Command usable in shell function names
+func (c *Command) functionName() string {
+	return strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(c.Name)
+}
+
+// bashCompletion returns the bash completion script, which walks the words to the current one through the
+// tree of the Commands and completes the SubCommands, the aliases and the flags of the reached Command.
+func (c *Command) bashCompletion() string {
+	nodes := c.completionNodes()
+	name := c.functionName()
+	script := &strings.Builder{}
+	fmt.Fprintf(script, "# bash completion for %s\n\n", c.Name)
+	fmt.Fprintf(script, "__%s_complete() {\n", name)
+	fmt.Fprintf(script, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" path=\"\" word i\n")
+	fmt.Fprintf(script, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(script, "        word=\"${COMP_WORDS[i]}\"\n")
+	fmt.Fprintf(script, "        case \"${path}:${word}\" in\n")
+	for _, node := range nodes {
+		for _, subCommand := range node.command.SubCommands {
+			childPath := strings.TrimSpace(node.path + " " + subCommand.Name)
+			patterns := []string{}
+			for _, word := range append([]string{subCommand.Name}, subCommand.Aliases...) {
+				patterns = append(patterns, fmt.Sprintf("%q", node.path+":"+word))
+			}
+			fmt.Fprintf(script, "            %s) path=%q ;;\n", strings.Join(patterns, "|"), childPath)
+		}
+	}
+	fmt.Fprintf(script, "        esac\n")
+	fmt.Fprintf(script, "    done\n")
+	fmt.Fprintf(script, "    local words\n")
+	fmt.Fprintf(script, "    case \"${path}\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(script, "        %q) words=%q ;;\n", node.path, strings.Join(append(node.commandWords(), node.flagWords()...), " "))
+	}
+	fmt.Fprintf(script, "    esac\n")
+	fmt.Fprintf(script, "    COMPREPLY=($(compgen -W \"${words}\" -- \"${cur}\"))\n")
+	fmt.Fprintf(script, "}\n\n")
+	fmt.Fprintf(script, "complete -o default -F __%s_complete %s\n", name, c.Name)
+	return script.String()
+}
+
+// fishCompletion returns the fish completion script, with the descriptions of the SubCommands and the flags.
+func (c *Command) fishCompletion() string {
+	nodes := c.completionNodes()
+	name := c.functionName()
+	script := &strings.Builder{}
+	fmt.Fprintf(script, "# fish completion for %s\n\n", c.Name)
+
+	// __name_path prints the canonical path of the Commands on the command line, like "items get"
+	transitions := map[string]map[string]string{}
+	for _, node := range nodes {
+		for _, subCommand := range node.command.SubCommands {
+			childPath := strings.TrimSpace(node.path + " " + subCommand.Name)
+			for _, word := range append([]string{subCommand.Name}, subCommand.Aliases...) {
+				if transitions[node.path] == nil {
+					transitions[node.path] = map[string]string{}
+				}
+				transitions[node.path][word] = childPath
+			}
+		}
+	}
+	fmt.Fprintf(script, "function __%s_path\n", name)
+	fmt.Fprintf(script, "    set -l path ''\n")
+	fmt.Fprintf(script, "    for word in (commandline -opc)[2..-1]\n")
+	fmt.Fprintf(script, "        switch \"$path:$word\"\n")
+	keys := []string{}
+	for path, words := range transitions {
+		for word := range words {
+			keys = append(keys, path+":"+word)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts := strings.SplitN(key, ":", 2)
+		fmt.Fprintf(script, "            case %s\n", fishQuote(key))
+		fmt.Fprintf(script, "                set path %s\n", fishQuote(transitions[parts[0]][parts[1]]))
+	}
+	fmt.Fprintf(script, "        end\n")
+	fmt.Fprintf(script, "    end\n")
+	fmt.Fprintf(script, "    echo $path\n")
+	fmt.Fprintf(script, "end\n\n")
+
+	fmt.Fprintf(script, "complete -c %s -f\n", c.Name)
+	for _, node := range nodes {
+		condition := "-n " + fishQuote(fmt.Sprintf("test (__%s_path) = \"%s\"", name, node.path))
+		for _, subCommand := range node.command.SubCommands {
+			for _, word := range append([]string{subCommand.Name}, subCommand.Aliases...) {
+				fmt.Fprintf(script, "complete -c %s %s -a %s -d %s\n", c.Name, condition, fishQuote(word), fishQuote(subCommand.Short))
+			}
+		}
+		for _, f := range node.command.flags(node.parents) {
+			line := fmt.Sprintf("complete -c %s %s -l %s", c.Name, condition, fishQuote(f.Name))
+			if f.Short != "" {
+				line += " -s " + fishQuote(f.Short)
+			}
+			if f.kind != kindBool {
+				line += " -r"
+			}
+			fmt.Fprintf(script, "%s -d %s\n", line, fishQuote(f.Usage))
+		}
+	}
+	return script.String()
+}
+
+// fishQuote returns the single quoted fish string
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// InterruptedError is the cause of the cancellation of the context of ExecuteContext by a signal
+type InterruptedError struct {
+	Signal os.Signal
+}
+
+// Error implements the error interface
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("Interrupted by signal: %v", e.Signal)
+}
+
+// TimeoutError is the cause of the cancellation of the context of a Command by its Timeout
+type TimeoutError struct {
+	// Path is the path of the Command, like "app migrate upall"
+	Path    string
+	Timeout time.Duration
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.Path, e.Timeout)
+}
+
+// notifyContext returns a copy of the context, which is cancelled with an InterruptedError by the first
+// SIGINT or SIGTERM. The signals are restored to their default behaviour after the first one,
+// so the next one terminates the process, even if the task does not return.
+func notifyContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			cancel(&InterruptedError{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(nil)
+	}
+}
+
+// withTimeout returns a copy of the context, which is cancelled with a TimeoutError after the Timeout
+// of the Command, or of its closest parent with a Timeout.
+func (c *Command) withTimeout(ctx context.Context, parents []*Command) (context.Context, context.CancelFunc) {
+	timeout := c.Timeout
+	for i := len(parents) - 1; i >= 0 && timeout == 0; i-- {
+		timeout = parents[i].Timeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	cause := &TimeoutError{Path: strings.Join(commandPath(parents, c), " "), Timeout: timeout}
+	return context.WithTimeoutCause(ctx, timeout, cause)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (cts *CLITestSuite) TestExecuteContext() {
+	type ctxKey struct{}
+	var received context.Context
+	cmd := NewCommand("app").WithSubCommands(
+		NewCommand("task").WithContextTask(func(ctx context.Context, args []string) error {
+			received = ctx
+			return nil
+		}),
+		NewCommand("run").WithArgs(StringArg("name", "Name")).WithRun(func(inv *Invocation) error {
+			received = inv.Context()
+			return nil
+		}),
+	)
+
+	parent := context.WithValue(context.Background(), ctxKey{}, "value")
+	cts.NoError(cmd.ExecuteContext(parent, []string{"task"}))
+	cts.Equal("value", received.Value(ctxKey{}), "The ContextTask should receive the context")
+	cts.NoError(cmd.ExecuteContext(parent, []string{"run", "x"}))
+	cts.Equal("value", received.Value(ctxKey{}), "The Run function should receive the context")
+	cts.Error(received.Err(), "The context should be cancelled after the execution")
+}
+
+func (cts *CLITestSuite) TestTimeout() {
+	wait := func(ctx context.Context, args []string) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}
+	cmd := NewCommand("app").WithSubCommands(
+		NewCommand("migrate").WithTimeout(10*time.Millisecond).WithSubCommands(
+			NewCommand("upall").WithContextTask(wait),
+			NewCommand("fail").WithContextTask(func(ctx context.Context, args []string) error {
+				<-ctx.Done()
+				return errors.New("Failed to migrate")
+			}),
+		),
+	)
+
+	err := cmd.Execute([]string{"migrate", "upall"})
+	cts.EqualError(err, "app migrate upall timed out after 10ms")
+	cts.Equal(ExitCodeTimeout, ExitCode(err))
+
+	err = cmd.Execute([]string{"migrate", "fail"})
+	cts.EqualError(err, "app migrate fail timed out after 10ms: Failed to migrate")
+	cts.Equal(ExitCodeTimeout, ExitCode(err))
+}
+
+func (cts *CLITestSuite) TestSignal() {
+	started := make(chan struct{})
+	cmd := NewCommand("app").WithContextTask(func(ctx context.Context, args []string) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+
+	go func() {
+		<-started
+		process, err := os.FindProcess(os.Getpid())
+		cts.NoError(err)
+		cts.NoError(process.Signal(syscall.SIGTERM))
+	}()
+	err := cmd.ExecuteContext(context.Background(), nil)
+	cts.EqualError(err, "Interrupted by signal: terminated")
+	cts.Equal(ExitCodeTerminated, ExitCode(err))
+}
+
+func (cts *CLITestSuite) TestExitCode() {
+	cts.Equal(ExitCodeOK, ExitCode(nil))
+	cts.Equal(ExitCodeFailure, ExitCode(errors.New("Failed")))
+	cts.Equal(3, ExitCode(errors.Wrap(&ExitError{Code: 3, Err: errors.New("Failed")}, "Wrapped")))
+
+	cmd := NewCommand("app").
+		WithRun(EndWithHelp).
+		WithOutput(ioutil.Discard).
+		WithSubCommands(NewCommand("get").WithArgs(IntArg("index", "Index")))
+	cts.Equal(ExitCodeUsage, ExitCode(cmd.Execute([]string{"get", "x"})), "Parse errors should be usage errors")
+	cts.Equal(ExitCodeUsage, ExitCode(cmd.Execute([]string{"nope"})), "Invalid commands should be usage errors")
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Exit codes of the errors returned by Execute and ExecuteContext, see ExitCode
+const (
+	ExitCodeOK          = 0
+	ExitCodeFailure     = 1
+	ExitCodeUsage       = 2
+	ExitCodeTimeout     = 124
+	ExitCodeInterrupted = 130 // 128 + SIGINT
+	ExitCodeTerminated  = 143 // 128 + SIGTERM
+)
+
+// ExitError is an error with the exit code of the process. The tasks can return an ExitError
+// to exit with their own code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("Exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Cause returns the error, to support the pkg/errors.Cause
+func (e *ExitError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the error, to support the errors.Is and errors.As
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the error returned by Execute or ExecuteContext, like
+//
+//	if err := rootCommand.ExecuteContext(context.Background(), os.Args[1:]); err != nil {
+//		fmt.Println("Error:", err)
+//		os.Exit(cli.ExitCode(err))
+//	}
+//
+// It is the Code of the ExitError, ExitCodeUsage for the ParseErrors, ExitCodeOK for nil
+// and ExitCodeFailure otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	exitErr := &ExitError{}
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	parseErr := &ParseError{}
+	if errors.As(err, &parseErr) {
+		return ExitCodeUsage
+	}
+	return ExitCodeFailure
+}
+
+// exitError returns the error of the task as an ExitError with the cause, if the context of the task
+// was cancelled by a signal or a timeout.
+func exitError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	cause := context.Cause(ctx)
+	code := ExitCodeFailure
+	interrupted := &InterruptedError{}
+	timeout := &TimeoutError{}
+	switch {
+	case errors.As(cause, &interrupted):
+		code = ExitCodeInterrupted
+		if interrupted.Signal == syscall.SIGTERM {
+			code = ExitCodeTerminated
+		}
+	case errors.As(cause, &timeout):
+		code = ExitCodeTimeout
+	default:
+		return err
+	}
+	// The errors of the context do not tell why it was cancelled
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return &ExitError{Code: code, Err: cause}
+	}
+	return &ExitError{Code: code, Err: errors.Wrap(err, cause.Error())}
+}
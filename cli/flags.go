@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"flag"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// valueKind is the type of the value of a Flag or an Arg
+type valueKind int
+
+const (
+	kindBool valueKind = iota
+	kindString
+	kindInt
+	kindDuration
+	kindStringSlice
+)
+
+// String returns the name of the value kind shown in the help, like --limit int
+func (k valueKind) String() string {
+	switch k {
+	case kindBool:
+		return "bool"
+	case kindInt:
+		return "int"
+	case kindDuration:
+		return "duration"
+	}
+	return "string"
+}
+
+// Flag is a declared --flag of a Command
+type Flag struct {
+	Name    string
+	Short   string
+	Usage   string
+	Default interface{}
+	kind    valueKind
+}
+
+// BoolFlag creates a new bool Flag, which is false by default and true if it is present
+func BoolFlag(name, usage string) *Flag {
+	return &Flag{Name: name, Usage: usage, Default: false, kind: kindBool}
+}
+
+// StringFlag creates a new string Flag with the default value
+func StringFlag(name, defaultValue, usage string) *Flag {
+	return &Flag{Name: name, Usage: usage, Default: defaultValue, kind: kindString}
+}
+
+// IntFlag creates a new int Flag with the default value
+func IntFlag(name string, defaultValue int, usage string) *Flag {
+	return &Flag{Name: name, Usage: usage, Default: defaultValue, kind: kindInt}
+}
+
+// DurationFlag creates a new time.Duration Flag with the default value, like --timeout 30s
+func DurationFlag(name string, defaultValue time.Duration, usage string) *Flag {
+	return &Flag{Name: name, Usage: usage, Default: defaultValue, kind: kindDuration}
+}
+
+// StringSliceFlag creates a new repeated string Flag, every occurrence adds a value, like --tag a --tag b
+func StringSliceFlag(name, usage string) *Flag {
+	return &Flag{Name: name, Usage: usage, Default: []string{}, kind: kindStringSlice}
+}
+
+// validate checks if the Default of the Flag matches its type. The Flags declared without the constructors
+// (like &cli.Flag{Name: "x"}) are bool Flags.
+func (f *Flag) validate() error {
+	var expected interface{}
+	switch f.kind {
+	case kindBool:
+		expected = false
+	case kindInt:
+		expected = 0
+	case kindDuration:
+		expected = time.Duration(0)
+	case kindStringSlice:
+		expected = []string{}
+	default:
+		expected = ""
+	}
+	if reflect.TypeOf(f.Default) != reflect.TypeOf(expected) {
+		return errors.Errorf("flag --%s has a default value of type %T instead of %T", f.Name, f.Default, expected)
+	}
+	return nil
+}
+
+// WithShort adds a one letter short name to the Flag, like -v for --verbose
+func (f *Flag) WithShort(short string) *Flag {
+	f.Short = short
+	return f
+}
+
+// define defines the Flag (and its short name) on the flag.FlagSet, and returns the function
+// which returns the parsed value.
+func (f *Flag) define(fs *flag.FlagSet) func() interface{} {
+	names := []string{f.Name}
+	if f.Short != "" {
+		names = append(names, f.Short)
+	}
+
+	switch f.kind {
+	case kindBool:
+		value := f.Default.(bool)
+		for _, name := range names {
+			fs.BoolVar(&value, name, value, f.Usage)
+		}
+		return func() interface{} { return value }
+	case kindInt:
+		value := f.Default.(int)
+		for _, name := range names {
+			fs.IntVar(&value, name, value, f.Usage)
+		}
+		return func() interface{} { return value }
+	case kindDuration:
+		value := f.Default.(time.Duration)
+		for _, name := range names {
+			fs.DurationVar(&value, name, value, f.Usage)
+		}
+		return func() interface{} { return value }
+	case kindStringSlice:
+		value := &stringSliceValue{}
+		for _, name := range names {
+			fs.Var(value, name, f.Usage)
+		}
+		return func() interface{} { return []string(*value) }
+	}
+	value := f.Default.(string)
+	for _, name := range names {
+		fs.StringVar(&value, name, value, f.Usage)
+	}
+	return func() interface{} { return value }
+}
+
+// stringSliceValue is a flag.Value collecting the values of a repeated flag
+type stringSliceValue []string
+
+// String implements the flag.Value interface
+func (s *stringSliceValue) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ",")
+}
+
+// Set implements the flag.Value interface
+func (s *stringSliceValue) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// Arg is a declared named positional argument of a Command
+type Arg struct {
+	Name     string
+	Usage    string
+	Required bool
+	Variadic bool
+	kind     valueKind
+}
+
+// StringArg creates a new required string Arg
+func StringArg(name, usage string) *Arg {
+	return &Arg{Name: name, Usage: usage, Required: true, kind: kindString}
+}
+
+// IntArg creates a new required int Arg
+func IntArg(name, usage string) *Arg {
+	return &Arg{Name: name, Usage: usage, Required: true, kind: kindInt}
+}
+
+// DurationArg creates a new required time.Duration Arg
+func DurationArg(name, usage string) *Arg {
+	return &Arg{Name: name, Usage: usage, Required: true, kind: kindDuration}
+}
+
+// VariadicArg creates a new required Arg, which receives every remaining positional argument as a string slice.
+// It must be the last Arg of the Command.
+func VariadicArg(name, usage string) *Arg {
+	return &Arg{Name: name, Usage: usage, Required: true, Variadic: true, kind: kindStringSlice}
+}
+
+// Optional makes the Arg optional, the missing optional Args have zero values
+func (a *Arg) Optional() *Arg {
+	a.Required = false
+	return a
+}
+
+// parse parses the value of the Arg
+func (a *Arg) parse(value string) (interface{}, error) {
+	switch a.kind {
+	case kindInt:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Errorf("Invalid value %q for argument <%s>: not an integer", value, a.Name)
+		}
+		return parsed, nil
+	case kindDuration:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.Errorf("Invalid value %q for argument <%s>: not a duration", value, a.Name)
+		}
+		return parsed, nil
+	}
+	return value, nil
+}
+
+// zero returns the zero value of the Arg
+func (a *Arg) zero() interface{} {
+	switch a.kind {
+	case kindInt:
+		return 0
+	case kindDuration:
+		return time.Duration(0)
+	case kindStringSlice:
+		return []string{}
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+// helpFlags are the flags, which print the help of the Command, unless the Command declares them
+var helpFlags = []string{"-h", "-help", "--help"}
+
+// WithShort adds the supplied one line description to the Command, which is shown in the help of its parent
+func (c *Command) WithShort(short string) *Command {
+	c.Short = short
+	return c
+}
+
+// WithLong adds the supplied long description to the Command, which is shown in its help
+func (c *Command) WithLong(long string) *Command {
+	c.Long = long
+	return c
+}
+
+// WithExamples adds the supplied examples to the Command, like "app items get 2"
+func (c *Command) WithExamples(examples ...string) *Command {
+	c.Examples = append(c.Examples, examples...)
+	return c
+}
+
+// WithOutput sets the writer of the help of the Command and its SubCommands. Defaults to os.Stdout.
+func (c *Command) WithOutput(output io.Writer) *Command {
+	c.output = output
+	return c
+}
+
+// Help returns the help of the Command generated from its descriptions, examples, flags, args and SubCommands
+func (c *Command) Help() string {
+	return c.help(nil)
+}
+
+// EndWithHelp is a Run function, which prints the help of the Command and returns with an error
+// depending on the amount of remaining args (no command / invalid command), like EndWithMessage.
+// The invalid command error suggests the similar SubCommands. The exit code of the error is ExitCodeUsage.
+func EndWithHelp(inv *Invocation) error {
+	fmt.Fprint(inv.Command.out(inv.parents), inv.Command.help(inv.parents))
+	if len(inv.Args) > 0 {
+		return &ExitError{Code: ExitCodeUsage, Err: inv.Command.invalidCommandError(inv.Args[0])}
+	}
+	return &ExitError{Code: ExitCodeUsage, Err: errors.New("Command is missing")}
+}
+
+// out returns the output of the Command, or of its closest parent with an output
+func (c *Command) out(parents []*Command) io.Writer {
+	if c.output != nil {
+		return c.output
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		if parents[i].output != nil {
+			return parents[i].output
+		}
+	}
+	return os.Stdout
+}
+
+// helpRequested reports if the flags before the first positional argument (or a "--") contain a help flag,
+// which is not declared by the Command. The arguments after the first positional argument are passed
+// as they are, like app exec grep -h pattern.
+func (c *Command) helpRequested(parents []*Command, args []string) bool {
+	flags := map[string]*Flag{}
+	for _, f := range c.flags(parents) {
+		if f.Name == "help" || f.Short == "h" {
+			return false
+		}
+		flags[f.Name] = f
+		if f.Short != "" {
+			flags[f.Short] = f
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if match(arg, helpFlags) {
+			return true
+		}
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			return false
+		}
+		// the value of a non-boolean flag may be the next argument, like --name -h
+		name := strings.TrimLeft(arg, "-")
+		if f, ok := flags[name]; ok && f.kind != kindBool {
+			i++
+		}
+	}
+	return false
+}
+
+// helpTarget returns the Command and its parents at the path of the help subcommand, like app help items get
+func (c *Command) helpTarget(parents []*Command, args []string) (*Command, []*Command) {
+	target := c
+	for _, arg := range args {
+		subCommand, _ := target.lookup(parents, arg)
+		if subCommand == nil {
+			break
+		}
+		parents = append(parents, target)
+		target = subCommand
+	}
+	return target, parents
+}
+
+// subCommand returns the SubCommand with the name or alias, or nil
+func (c *Command) subCommand(name string) *Command {
+	for _, subCommand := range c.SubCommands {
+		// Check for both the Command's Name and the Aliases too
+		if subCommand.Name == name || match(name, subCommand.Aliases) {
+			return subCommand
+		}
+	}
+	return nil
+}
+
+// usage returns the usage line of the Command, like app items get [flags] <index>
+func (c *Command) usage(parents []*Command) string {
+	parts := commandPath(parents, c)
+	if len(c.SubCommands) > 0 {
+		parts = append(parts, "<subcommand>")
+	}
+	if len(c.flags(parents)) > 0 {
+		parts = append(parts, "[flags]")
+	}
+	for _, arg := range c.Args {
+		name := arg.Name
+		if arg.Variadic {
+			name += "..."
+		}
+		if arg.Required {
+			parts = append(parts, "<"+name+">")
+		} else {
+			parts = append(parts, "["+name+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// help returns the help of the Command with its parents
+func (c *Command) help(parents []*Command) string {
+	help := &strings.Builder{}
+	if description := c.Long; description != "" || c.Short != "" {
+		if description == "" {
+			description = c.Short
+		}
+		fmt.Fprintf(help, "%s\n\n", strings.TrimSpace(description))
+	}
+	fmt.Fprintf(help, "Usage:\n  %s\n", c.usage(parents))
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(help, "\nAliases:\n  %s\n", strings.Join(append([]string{c.Name}, c.Aliases...), ", "))
+	}
+	if len(c.Examples) > 0 {
+		fmt.Fprintf(help, "\nExamples:\n")
+		for _, example := range c.Examples {
+			fmt.Fprintf(help, "  $ %s\n", example)
+		}
+	}
+
+	if len(c.SubCommands) > 0 {
+		fmt.Fprintf(help, "\nAvailable subcommands:\n")
+		table := tabwriter.NewWriter(help, 0, 4, 2, ' ', 0)
+		for _, subCommand := range c.SubCommands {
+			description := subCommand.Short
+			if len(subCommand.Aliases) > 0 {
+				description = strings.TrimSpace(fmt.Sprintf("%s (aliases: %s)", description, strings.Join(subCommand.Aliases, ", ")))
+			}
+			fmt.Fprintf(table, "  %s\t%s\n", subCommand.Name, description)
+		}
+		table.Flush()
+	}
+	if len(c.Args) > 0 {
+		fmt.Fprintf(help, "\nArguments:\n")
+		table := tabwriter.NewWriter(help, 0, 4, 2, ' ', 0)
+		for _, arg := range c.Args {
+			fmt.Fprintf(table, "  %s\t%s\n", arg.Name, arg.Usage)
+		}
+		table.Flush()
+	}
+
+	inherited := []*Flag{}
+	for _, parent := range parents {
+		inherited = append(inherited, parent.PersistentFlags...)
+	}
+	writeFlags(help, "Flags", append(append([]*Flag{}, c.Flags...), c.PersistentFlags...))
+	writeFlags(help, "Global flags", inherited)
+
+	if len(c.SubCommands) > 0 {
+		fmt.Fprintf(help, "\nUse \"%s <subcommand> --help\" for more information about a subcommand.\n",
+			strings.Join(commandPath(parents, c), " "))
+	}
+	return help.String()
+}
+
+// writeFlags writes the table of the Flags with the title
+func writeFlags(w io.Writer, title string, flags []*Flag) {
+	if len(flags) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n", title)
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, f := range flags {
+		names := "    --" + f.Name
+		if f.Short != "" {
+			names = "-" + f.Short + ", --" + f.Name
+		}
+		usage := f.Usage
+		switch f.kind {
+		case kindBool:
+		case kindStringSlice:
+			names += " " + f.kind.String()
+			usage += " (repeatable)"
+		default:
+			names += " " + f.kind.String()
+			if value := fmt.Sprint(f.Default); value != "" && value != "0" && value != "0s" {
+				usage += fmt.Sprintf(" (default %s)", value)
+			}
+		}
+		fmt.Fprintf(table, "  %s\t%s\n", names, strings.TrimSpace(usage))
+	}
+	table.Flush()
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func (cts *CLITestSuite) getHelpTestCommand(output *bytes.Buffer) *Command {
+	getCmd := NewCommand("get").
+		WithAliases("g").
+		WithShort("Get an item").
+		WithLong("Get an item by its index.").
+		WithExamples("app items get 2").
+		WithFlags(IntFlag("count", 1, "Number of items").WithShort("n")).
+		WithArgs(IntArg("index", "Index of the item"), StringArg("format", "Output format").Optional()).
+		WithRun(func(inv *Invocation) error { return nil })
+	itemsCmd := NewCommand("items").
+		WithShort("Manage items").
+		WithRun(EndWithHelp).
+		WithSubCommands(getCmd, NewCommand("add").WithShort("Add items"))
+	root := NewCommand("app").
+		WithLong("Example application").
+		WithOutput(output).
+		WithPersistentFlags(BoolFlag("verbose", "Verbose output").WithShort("v")).
+		WithSubCommands(itemsCmd)
+	return root.WithSubCommands(NewCompletionCommand(root))
+}
+
+func (cts *CLITestSuite) TestHelp() {
+	output := &bytes.Buffer{}
+	cmd := cts.getHelpTestCommand(output)
+
+	cts.NoError(cmd.Execute([]string{"items", "get", "--help"}))
+	help := output.String()
+	cts.Contains(help, "Get an item by its index.")
+	cts.Contains(help, "app items get [flags] <index> [format]")
+	cts.Contains(help, "get, g", "Help should list the aliases")
+	cts.Contains(help, "$ app items get 2")
+	cts.Contains(help, "-n, --count int")
+	cts.Contains(help, "Global flags:")
+	cts.Contains(help, "-v, --verbose")
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"help", "items", "g"}))
+	cts.Equal(help, output.String(), "The help subcommand should print the same help")
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"-h"}))
+	cts.Contains(output.String(), "Example application")
+	cts.Contains(output.String(), "completion")
+
+	output.Reset()
+	cts.EqualError(cmd.Execute([]string{"items", "nope"}), "Invalid Command: nope")
+	cts.Contains(output.String(), "get  Get an item (aliases: g)")
+	cts.Contains(output.String(), "add  Add items")
+
+	output.Reset()
+	cts.EqualError(cmd.Execute([]string{"items"}), "Command is missing")
+	cts.Contains(output.String(), "app items <subcommand> [flags]")
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"items", "get", "-v", "-n", "3", "--help"}))
+	cts.Contains(output.String(), "Get an item by its index.", "Help should be found after the flags and their values")
+	output.Reset()
+	cts.Error(cmd.Execute([]string{"items", "get", "2", "-h"}), "Help flags after the positional args should be parsed")
+	cts.Empty(output.String())
+}
+
+func (cts *CLITestSuite) TestHelpTask() {
+	output := &bytes.Buffer{}
+	var received []string
+	cmd := NewCommand("app").WithOutput(output).WithSubCommands(
+		NewCommand("reset").WithShort("Reset everything").WithContextTask(func(ctx context.Context, args []string) error {
+			received = args
+			return nil
+		}),
+	)
+
+	for _, args := range [][]string{{"--help"}, {"-h"}} {
+		output.Reset()
+		cts.NoError(cmd.Execute(append([]string{"reset"}, args...)))
+		cts.Nil(received, "The Task should not have been executed")
+		cts.Contains(output.String(), "Reset everything", "The help of the Task should have been printed")
+	}
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"reset", "grep", "-h", "foo"}))
+	cts.Equal([]string{"grep", "-h", "foo"}, received, "The arguments after a positional argument should be passed to the Task")
+	cts.Empty(output.String())
+}
+
+func (cts *CLITestSuite) TestCompletion() {
+	output := &bytes.Buffer{}
+	cmd := cts.getHelpTestCommand(output)
+
+	cts.NoError(cmd.Execute([]string{"completion", ShellBash}))
+	bash := output.String()
+	cts.Contains(bash, `"items:get"|"items:g") path="items get" ;;`)
+	cts.Contains(bash, `"items get") words="--verbose -v --count -n --help" ;;`)
+	cts.Contains(bash, "complete -o default -F __app_complete app")
+
+	zsh, err := cmd.Completion(ShellZsh)
+	cts.NoError(err)
+	cts.Contains(zsh, "#compdef app")
+
+	fish, err := cmd.Completion(ShellFish)
+	cts.NoError(err)
+	cts.Contains(fish, "case 'items:g'\n                set path 'items get'")
+	cts.Contains(fish, `complete -c app -n 'test (__app_path) = "items"' -a 'get' -d 'Get an item'`)
+
+	_, err = cmd.Completion("powershell")
+	cts.EqualError(err, "Unsupported shell powershell, use bash, zsh or fish")
+
+	// Check the syntax of the bash script, if bash is available
+	if bashPath, err := exec.LookPath("bash"); err == nil {
+		dir, err := ioutil.TempDir("", "completion")
+		cts.Require().NoError(err)
+		defer os.RemoveAll(dir)
+		script := filepath.Join(dir, "app.bash")
+		cts.Require().NoError(ioutil.WriteFile(script, []byte(bash), 0600))
+		out, err := exec.Command(bashPath, "-n", script).CombinedOutput()
+		cts.NoError(err, string(out))
+	}
+}
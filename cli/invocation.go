@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseError is returned if the flags or the positional arguments of a Command cannot be parsed
+type ParseError struct {
+	// Path is the path of the Command, like "app items get"
+	Path string
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Cause returns the parse error, to support the pkg/errors.Cause
+func (e *ParseError) Cause() error {
+	return e.Err
+}
+
+// Invocation is a parsed execution of a Command, with typed access to the values of the flags and the named args
+type Invocation struct {
+	// Command is the executed Command
+	Command *Command
+
+	// Path is the names of the executed Command and its parents, like ["app", "items", "get"]
+	Path []string
+
+	// Args are the positional arguments after the flags
+	Args []string
+
+	ctx     context.Context
+	values  map[string]interface{}
+	set     map[string]bool
+	parents []*Command
+}
+
+// Context returns the context of the execution, which is cancelled by the signals (ExecuteContext)
+// and the timeout of the Command
+func (inv *Invocation) Context() context.Context {
+	if inv.ctx == nil {
+		return context.Background()
+	}
+	return inv.ctx
+}
+
+// SetContext replaces the context of the execution, the PreRun hooks and the Middlewares can attach values to it
+// (like logger.Logger.WithContext) for the Run function and the ContextTask
+func (inv *Invocation) SetContext(ctx context.Context) {
+	inv.ctx = ctx
+}
+
+// value returns the value of the flag or named arg
+func (inv *Invocation) value(name string) interface{} {
+	return inv.values[name]
+}
+
+// Bool returns the value of the bool flag, false for unknown names
+func (inv *Invocation) Bool(name string) bool {
+	value, _ := inv.value(name).(bool)
+	return value
+}
+
+// String returns the value of the string flag or arg, an empty string for unknown names
+func (inv *Invocation) String(name string) string {
+	value, _ := inv.value(name).(string)
+	return value
+}
+
+// Int returns the value of the int flag or arg, 0 for unknown names
+func (inv *Invocation) Int(name string) int {
+	value, _ := inv.value(name).(int)
+	return value
+}
+
+// Duration returns the value of the duration flag or arg, 0 for unknown names
+func (inv *Invocation) Duration(name string) time.Duration {
+	value, _ := inv.value(name).(time.Duration)
+	return value
+}
+
+// StringSlice returns the values of the repeated flag or the variadic arg, nil for unknown names
+func (inv *Invocation) StringSlice(name string) []string {
+	value, _ := inv.value(name).([]string)
+	return value
+}
+
+// IsSet reports if the flag was present or the arg was supplied
+func (inv *Invocation) IsSet(name string) bool {
+	return inv.set[name]
+}
+
+// CommandPath returns the path of the executed Command, like "app items get"
+func (inv *Invocation) CommandPath() string {
+	return strings.Join(inv.Path, " ")
+}
+
+// commandPath returns the names of the parents and the Command
+func commandPath(parents []*Command, c *Command) []string {
+	path := make([]string, 0, len(parents)+1)
+	for _, parent := range parents {
+		path = append(path, parent.Name)
+	}
+	return append(path, c.Name)
+}
+
+// flags returns the Flags of the Command with the persistent Flags of its parents
+func (c *Command) flags(parents []*Command) []*Flag {
+	flags := []*Flag{}
+	for _, parent := range parents {
+		flags = append(flags, parent.PersistentFlags...)
+	}
+	flags = append(flags, c.PersistentFlags...)
+	return append(flags, c.Flags...)
+}
+
+// declaresArguments reports if the Command (or its parents) declares any flags or args,
+// otherwise the raw arguments are passed to the Task.
+func (c *Command) declaresArguments(parents []*Command) bool {
+	return len(c.flags(parents)) > 0 || len(c.Args) > 0
+}
+
+// parse parses the flags (anywhere before a "--") and the positional arguments of the Command
+func (c *Command) parse(parents []*Command, args []string) (*Invocation, error) {
+	inv := &Invocation{
+		Command: c,
+		Path:    commandPath(parents, c),
+		Args:    []string{},
+		values:  map[string]interface{}{},
+		set:     map[string]bool{},
+		parents: parents,
+	}
+	fail := func(err error) (*Invocation, error) {
+		return nil, &ParseError{Path: inv.CommandPath(), Err: err}
+	}
+
+	fs := flag.NewFlagSet(inv.CommandPath(), flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	flags := c.flags(parents)
+	parsed := make(map[string]func() interface{}, len(flags))
+	names := map[string]string{}
+	for _, f := range flags {
+		if fs.Lookup(f.Name) != nil || (f.Short != "" && fs.Lookup(f.Short) != nil) {
+			return fail(errors.Errorf("Flag --%s is declared more than once", f.Name))
+		}
+		parsed[f.Name] = f.define(fs)
+		names[f.Name] = f.Name
+		if f.Short != "" {
+			names[f.Short] = f.Name
+		}
+	}
+
+	rest := args
+	for len(rest) > 0 {
+		if err := fs.Parse(rest); err != nil {
+			return fail(err)
+		}
+		consumed := len(rest) - len(fs.Args())
+		if consumed > 0 && rest[consumed-1] == "--" {
+			inv.Args = append(inv.Args, fs.Args()...)
+			break
+		}
+		rest = fs.Args()
+		if len(rest) > 0 {
+			inv.Args = append(inv.Args, rest[0])
+			rest = rest[1:]
+		}
+	}
+
+	for name, value := range parsed {
+		inv.values[name] = value()
+	}
+	fs.Visit(func(f *flag.Flag) {
+		inv.set[names[f.Name]] = true
+	})
+
+	if err := c.bindArgs(inv); err != nil {
+		return fail(err)
+	}
+	return inv, nil
+}
+
+// bindArgs binds the positional arguments to the named Args of the Command
+func (c *Command) bindArgs(inv *Invocation) error {
+	if len(c.Args) == 0 {
+		return nil
+	}
+	positional := inv.Args
+	for i, arg := range c.Args {
+		inv.values[arg.Name] = arg.zero()
+		switch {
+		case arg.Variadic:
+			if len(positional) == 0 && arg.Required {
+				return errors.Errorf("Missing argument <%s...>", arg.Name)
+			}
+			inv.values[arg.Name] = append([]string{}, positional...)
+			inv.set[arg.Name] = len(positional) > 0
+			positional = nil
+		case len(positional) == 0:
+			if arg.Required {
+				return errors.Errorf("Missing argument <%s>", arg.Name)
+			}
+		default:
+			value, err := arg.parse(positional[0])
+			if err != nil {
+				return err
+			}
+			inv.values[arg.Name] = value
+			inv.set[arg.Name] = true
+			positional = positional[1:]
+		}
+		if arg.Variadic && i != len(c.Args)-1 {
+			return errors.Errorf("Variadic argument <%s...> must be the last argument", arg.Name)
+		}
+	}
+	if len(positional) > 0 {
+		return errors.Errorf("Unexpected argument %s", positional[0])
+	}
+	return nil
+}
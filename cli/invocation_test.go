@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (cts *CLITestSuite) TestFlagsAndArgs() {
+	var inv *Invocation
+	run := func(i *Invocation) error {
+		inv = i
+		return nil
+	}
+	getCmd := NewCommand("get").
+		WithFlags(
+			IntFlag("count", 1, "Number of items"),
+			DurationFlag("timeout", time.Second, "Timeout"),
+			StringSliceFlag("tag", "Tags").WithShort("t"),
+		).
+		WithArgs(IntArg("index", "Index of the item"), StringArg("format", "Output format").Optional()).
+		WithRun(run)
+	addCmd := NewCommand("add").
+		WithArgs(VariadicArg("items", "Items to add")).
+		WithRun(run)
+	cmd := NewCommand("app").
+		WithPersistentFlags(BoolFlag("verbose", "Verbose output").WithShort("v"), StringFlag("env", "dev", "Environment")).
+		WithSubCommands(NewCommand("items").WithSubCommands(getCmd, addCmd))
+
+	cts.NoError(cmd.Execute([]string{"items", "get", "-v", "2", "--count=3", "-t", "a", "--tag", "b", "--timeout", "1m"}))
+	cts.Equal("app items get", inv.CommandPath())
+	cts.True(inv.Bool("verbose"), "Persistent flag should have been inherited")
+	cts.True(inv.IsSet("verbose"), "Short flag should mark the flag as set")
+	cts.Equal("dev", inv.String("env"), "Default value should have been used")
+	cts.False(inv.IsSet("env"))
+	cts.Equal(3, inv.Int("count"))
+	cts.Equal(time.Minute, inv.Duration("timeout"))
+	cts.Equal([]string{"a", "b"}, inv.StringSlice("tag"))
+	cts.Equal(2, inv.Int("index"))
+	cts.Equal("", inv.String("format"), "Missing optional arg should be empty")
+	cts.Equal([]string{"2"}, inv.Args)
+
+	cts.NoError(cmd.Execute([]string{"items", "add", "Hat", "--env", "prod", "--", "--not-a-flag"}))
+	cts.Equal([]string{"Hat", "--not-a-flag"}, inv.StringSlice("items"))
+	cts.Equal("prod", inv.String("env"))
+
+	for msg, args := range map[string][]string{
+		"app items get: Missing argument <index>":                                 {"items", "get"},
+		"app items get: Invalid value \"x\" for argument <index>: not an integer": {"items", "get", "x"},
+		"app items get: Unexpected argument extra":                                {"items", "get", "1", "json", "extra"},
+		"app items get: flag provided but not defined: -unknown":                  {"items", "get", "-unknown"},
+		"app items add: Missing argument <items...>":                              {"items", "add"},
+	} {
+		err := cmd.Execute(args)
+		cts.EqualError(err, msg)
+		parseErr := &ParseError{}
+		cts.True(errors.As(err, &parseErr), "Parse errors should be ParseErrors")
+	}
+}
+
+func (cts *CLITestSuite) TestTaskWithFlags() {
+	testList := []string{}
+	cmd := NewCommand("test-command").
+		WithFlags(BoolFlag("dry-run", "Dry run")).
+		WithTask(func(args []string) error {
+			testList = args
+			return nil
+		})
+
+	cts.NoError(cmd.Execute([]string{"arg1", "--dry-run", "arg2"}))
+	cts.Equal([]string{"arg1", "arg2"}, testList, "The Task should receive the positional arguments")
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxSuggestionDistance is the largest edit distance of the suggested SubCommands
+const maxSuggestionDistance = 2
+
+// WithPrefixMatching enables the unique prefix matching of the SubCommands of the Command and its SubCommands,
+// like app it l for app items list. A prefix matching more than one SubCommand is an error.
+func (c *Command) WithPrefixMatching() *Command {
+	c.PrefixMatching = true
+	return c
+}
+
+// prefixMatching reports if the Command or any of its parents enabled the prefix matching
+func (c *Command) prefixMatching(parents []*Command) bool {
+	if c.PrefixMatching {
+		return true
+	}
+	for _, parent := range parents {
+		if parent.PrefixMatching {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the SubCommand with the name or alias, or with the unique prefix if the prefix matching is enabled.
+// It returns nil if nothing matches, and an error if the prefix matches more than one SubCommand.
+func (c *Command) lookup(parents []*Command, name string) (*Command, error) {
+	if subCommand := c.subCommand(name); subCommand != nil || !c.prefixMatching(parents) || name == "" {
+		return subCommand, nil
+	}
+	matches := []*Command{}
+	for _, subCommand := range c.SubCommands {
+		for _, word := range append([]string{subCommand.Name}, subCommand.Aliases...) {
+			if strings.HasPrefix(word, name) {
+				matches = append(matches, subCommand)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, match.Name)
+	}
+	return nil, &ExitError{
+		Code: ExitCodeUsage,
+		Err: errors.Errorf("Ambiguous command %s in %s, it matches %s",
+			name, strings.Join(commandPath(parents, c), " "), strings.Join(names, ", ")),
+	}
+}
+
+// Suggestions returns the names of the SubCommands, whose name or one of its aliases is similar to the name
+// (by edit distance) or starts with it, the most similar ones first
+func (c *Command) Suggestions(name string) []string {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	suggestions := []suggestion{}
+	for _, subCommand := range c.SubCommands {
+		best := -1
+		for _, word := range append([]string{subCommand.Name}, subCommand.Aliases...) {
+			distance := editDistance(strings.ToLower(name), strings.ToLower(word))
+			if len(name) > 1 && strings.HasPrefix(word, name) {
+				distance = 0
+			}
+			if distance <= maxSuggestionDistance && distance < len(word) && (best < 0 || distance < best) {
+				best = distance
+			}
+		}
+		if best >= 0 {
+			suggestions = append(suggestions, suggestion{name: subCommand.Name, distance: best})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	names := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// invalidCommandError returns the error of the invalid subcommand name, with the suggestions of the Command
+func (c *Command) invalidCommandError(name string) error {
+	if suggestions := c.Suggestions(name); len(suggestions) > 0 {
+		return errors.Errorf("Invalid Command: %s, did you mean %s?", name, strings.Join(suggestions, " or "))
+	}
+	return errors.Errorf("Invalid Command: %s", name)
+}
+
+// editDistance returns the optimal string alignment distance of the strings: the number of the insertions,
+// deletions, substitutions and transpositions of adjacent characters to change a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
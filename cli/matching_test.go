@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"io/ioutil"
+)
+
+func (cts *CLITestSuite) getMatchingTestCommand() *Command {
+	run := func(inv *Invocation) error { return nil }
+	return NewCommand("app").
+		WithOutput(ioutil.Discard).
+		WithRun(EndWithHelp).
+		WithSubCommands(
+			NewCommand("items").WithAliases("item").WithRun(EndWithHelp).WithSubCommands(
+				NewCommand("list").WithAliases("ls").WithRun(run),
+				NewCommand("lint").WithRun(run),
+			),
+			NewCommand("import").WithRun(run),
+			NewCommand("version").WithRun(run),
+		)
+}
+
+func (cts *CLITestSuite) TestSuggestions() {
+	cmd := cts.getMatchingTestCommand()
+
+	cts.Equal([]string{"items"}, cmd.Suggestions("itmes"), "Transpositions should be suggested")
+	cts.Equal([]string{"version"}, cmd.Suggestions("ver"), "Prefixes should be suggested")
+	cts.Equal([]string{"items"}, cmd.Suggestions("itms"), "Aliases should be suggested once")
+	cts.Empty(cmd.Suggestions("xyz"))
+
+	err := cmd.Execute([]string{"items", "lsit"})
+	cts.EqualError(err, "Invalid Command: lsit, did you mean list or lint?")
+	cts.Equal(ExitCodeUsage, ExitCode(err))
+	cts.EqualError(cmd.Execute([]string{"xyz"}), "Invalid Command: xyz")
+}
+
+func (cts *CLITestSuite) TestPrefixMatching() {
+	cmd := cts.getMatchingTestCommand()
+	cts.EqualError(cmd.Execute([]string{"ite", "list"}), "Invalid Command: ite, did you mean items?", "Prefix matching should be opt-in")
+
+	cmd.WithPrefixMatching()
+	cts.NoError(cmd.Execute([]string{"ite", "lis"}), "Unique prefixes should match, in the SubCommands too")
+	cts.NoError(cmd.Execute([]string{"v"}))
+
+	err := cmd.Execute([]string{"i"})
+	cts.EqualError(err, "Ambiguous command i in app, it matches items, import")
+	cts.Equal(ExitCodeUsage, ExitCode(err))
+	cts.EqualError(cmd.Execute([]string{"items", "li"}), "Ambiguous command li in app items, it matches list, lint")
+}
+
+func (cts *CLITestSuite) TestValidate() {
+	cts.NoError(cts.getMatchingTestCommand().Validate())
+
+	cmd := cts.getMatchingTestCommand()
+	cmd.SubCommands[0].WithSubCommands(NewCommand("lines").WithAliases("ls"))
+	cts.EqualError(cmd.Validate(), "Invalid command app items: subcommands list and lines share the name or alias ls")
+	cts.EqualError(cmd.Execute([]string{"version"}), "Invalid command app items: subcommands list and lines share the name or alias ls",
+		"The tree should be validated before the execution")
+
+	cmd = cts.getMatchingTestCommand().WithSubCommands(NewCommand("item"))
+	cts.EqualError(cmd.Validate(), "Invalid command app: subcommands items and item share the name or alias item")
+
+	cmd = cts.getMatchingTestCommand().WithPersistentFlags(BoolFlag("verbose", "Verbose").WithShort("v"))
+	cmd.SubCommands[1].WithFlags(BoolFlag("version", "Version").WithShort("v"))
+	cts.EqualError(cmd.Validate(), "Invalid command app import: flags --verbose and --version share the name v")
+
+	cmd = cts.getMatchingTestCommand().WithPersistentFlags(BoolFlag("verbose", "Verbose").WithShort("v"))
+	cmd.SubCommands[1].WithAliases("-v")
+	cts.EqualError(cmd.Validate(), "Invalid command app: alias -v of subcommand import shadows the flag --verbose")
+
+	cmd = cts.getMatchingTestCommand().WithFlags(&Flag{Name: "dry-run"})
+	cts.EqualError(cmd.Validate(), "Invalid command app: flag --dry-run has a default value of type <nil> instead of bool")
+	cmd = cts.getMatchingTestCommand().WithFlags(StringFlag("name", "", "Name"))
+	cmd.Flags[0].Default = 5
+	cts.EqualError(cmd.Validate(), "Invalid command app: flag --name has a default value of type int instead of string")
+	cts.EqualError(cmd.Execute([]string{"--name", "x"}), "Invalid command app: flag --name has a default value of type int instead of string",
+		"The flags should be validated before they are parsed")
+}
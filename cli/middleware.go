@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Handler executes an Invocation, like the Run function of a Command
+type Handler func(inv *Invocation) error
+
+// Middleware wraps the execution of the Commands, like the http middlewares wrap the http.Handlers
+type Middleware func(next Handler) Handler
+
+// WithPreRun adds the supplied hook to the Command, which is executed before the Run function or the Task of
+// the Command and its SubCommands. The hooks are executed from the root to the executed Command, once per invocation.
+// If a hook fails, the Command is not executed.
+func (c *Command) WithPreRun(preRun func(inv *Invocation) error) *Command {
+	c.PreRun = preRun
+	return c
+}
+
+// WithPostRun adds the supplied hook to the Command, which is executed after the Run function or the Task of
+// the Command and its SubCommands (even if they fail). The hooks are executed from the executed Command to the root,
+// except the hooks of the Commands whose PreRun hook failed or was not reached.
+func (c *Command) WithPostRun(postRun func(inv *Invocation) error) *Command {
+	c.PostRun = postRun
+	return c
+}
+
+// WithMiddlewares adds the supplied Middlewares to the Command, which wrap the execution (with the hooks)
+// of the Command and its SubCommands. The Middlewares of the root are the outermost ones.
+func (c *Command) WithMiddlewares(middlewares ...Middleware) *Command {
+	c.Middlewares = append(c.Middlewares, middlewares...)
+	return c
+}
+
+// handler returns the Handler of the Command, which executes the hooks, the Run function or the Task, wrapped by
+// the Middlewares of the Command and its parents
+func (c *Command) handler(parents []*Command) Handler {
+	path := append(append([]*Command{}, parents...), c)
+	handler := func(inv *Invocation) error {
+		for i, command := range path {
+			if command.PreRun == nil {
+				continue
+			}
+			if err := command.PreRun(inv); err != nil {
+				return postRun(path[:i], inv, err)
+			}
+		}
+		var err error
+		if c.Run != nil {
+			err = c.Run(inv)
+		} else {
+			err = c.task(inv.Context(), inv.Args)
+		}
+		return postRun(path, inv, err)
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		for j := len(path[i].Middlewares) - 1; j >= 0; j-- {
+			handler = path[i].Middlewares[j](handler)
+		}
+	}
+	return handler
+}
+
+// postRun executes the PostRun hooks of the Commands in reverse order, and returns the error of the execution,
+// or the first error of the hooks
+func postRun(path []*Command, inv *Invocation, err error) error {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].PostRun == nil {
+			continue
+		}
+		if hookErr := path[i].PostRun(inv); hookErr != nil && err == nil {
+			err = hookErr
+		}
+	}
+	return err
+}
+
+// Recover returns a Middleware, which recovers from the panics of the execution and returns them as errors
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = errors.Errorf("Recovered from panic in %s: %v", inv.CommandPath(), r)
+				}
+			}()
+			return next(inv)
+		}
+	}
+}
+
+// Timing returns a Middleware, which reports the elapsed time of the execution
+func Timing(report func(inv *Invocation, elapsed time.Duration)) Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) error {
+			start := time.Now()
+			defer func() {
+				report(inv, time.Since(start))
+			}()
+			return next(inv)
+		}
+	}
+}
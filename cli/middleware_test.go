@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (cts *CLITestSuite) TestHooks() {
+	calls := []string{}
+	hook := func(name string, err error) func(inv *Invocation) error {
+		return func(inv *Invocation) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+	type ctxKey struct{}
+	task := NewCommand("task").
+		WithPreRun(hook("task pre", nil)).
+		WithPostRun(hook("task post", nil)).
+		WithContextTask(func(ctx context.Context, args []string) error {
+			calls = append(calls, "task "+ctx.Value(ctxKey{}).(string))
+			return nil
+		})
+	cmd := NewCommand("app").
+		WithPreRun(func(inv *Invocation) error {
+			calls = append(calls, "app pre")
+			inv.SetContext(context.WithValue(inv.Context(), ctxKey{}, "db"))
+			return nil
+		}).
+		WithPostRun(hook("app post", nil)).
+		WithSubCommands(
+			NewCommand("items").
+				WithPostRun(hook("items post", errors.New("Failed to close"))).
+				WithSubCommands(task),
+		)
+
+	cts.EqualError(cmd.Execute([]string{"items", "task"}), "Failed to close", "The errors of the PostRun hooks should be returned")
+	cts.Equal([]string{"app pre", "task pre", "task db", "task post", "items post", "app post"}, calls)
+
+	calls = []string{}
+	task.WithPreRun(hook("task pre", errors.New("Failed to set up")))
+	cts.EqualError(cmd.Execute([]string{"items", "task"}), "Failed to set up")
+	cts.Equal([]string{"app pre", "task pre", "items post", "app post"}, calls, "The Task should not run if a PreRun hook fails")
+}
+
+func (cts *CLITestSuite) TestMiddlewares() {
+	calls := []string{}
+	middleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(inv *Invocation) error {
+				calls = append(calls, name+" before")
+				err := next(inv)
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+	var elapsed time.Duration
+	cmd := NewCommand("app").
+		WithMiddlewares(Recover(), middleware("app"), Timing(func(inv *Invocation, d time.Duration) { elapsed = d })).
+		WithSubCommands(
+			NewCommand("panic").
+				WithMiddlewares(middleware("panic")).
+				WithPreRun(func(inv *Invocation) error {
+					calls = append(calls, "pre")
+					return nil
+				}).
+				WithTask(func(args []string) error {
+					time.Sleep(time.Millisecond)
+					panic("boom")
+				}),
+		)
+
+	cts.EqualError(cmd.Execute([]string{"panic"}), "Recovered from panic in app panic: boom")
+	cts.Equal([]string{"app before", "panic before", "pre"}, calls, "The root Middlewares should be the outermost ones")
+	cts.True(elapsed >= time.Millisecond, "The elapsed time should have been reported")
+}
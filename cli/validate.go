@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Validate checks the tree of the Commands, it fails if two SubCommands of a Command share a name or an alias,
+// an alias of a SubCommand (like -v) shadows a flag of the Command, a Command (with the persistent flags of its
+// parents) declares a flag name more than once, or the default value of a Flag does not match its type.
+// Execute and ExecuteContext validate the tree before the execution, call it from a test to fail at build time.
+func (c *Command) Validate() error {
+	return c.validate(nil)
+}
+
+// validate checks the Command and its SubCommands with the parents of the Command
+func (c *Command) validate(parents []*Command) error {
+	path := strings.Join(commandPath(parents, c), " ")
+
+	flags := map[string]string{}
+	for _, f := range c.flags(parents) {
+		if err := f.validate(); err != nil {
+			return errors.Wrapf(err, "Invalid command %s", path)
+		}
+		for _, name := range []string{f.Name, f.Short} {
+			if name == "" {
+				continue
+			}
+			if other, ok := flags[name]; ok {
+				return errors.Errorf("Invalid command %s: flags --%s and --%s share the name %s", path, other, f.Name, name)
+			}
+			flags[name] = f.Name
+		}
+	}
+
+	words := map[string]string{}
+	for _, subCommand := range c.SubCommands {
+		for _, word := range append([]string{subCommand.Name}, subCommand.Aliases...) {
+			if other, ok := words[word]; ok {
+				return errors.Errorf("Invalid command %s: subcommands %s and %s share the name or alias %s", path, other, subCommand.Name, word)
+			}
+			words[word] = subCommand.Name
+			// the subcommands are matched before the flags are parsed, so the flag could not be used
+			if other, ok := flags[strings.TrimLeft(word, "-")]; ok && strings.HasPrefix(word, "-") {
+				return errors.Errorf("Invalid command %s: alias %s of subcommand %s shadows the flag --%s", path, word, subCommand.Name, other)
+			}
+		}
+	}
+
+	for _, subCommand := range c.SubCommands {
+		if err := subCommand.validate(append(parents, c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
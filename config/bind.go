@@ -0,0 +1,352 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pkg/errors"
+
+	"github.com/toolbox/constants"
+)
+
+// Struct tags understood by VariablesFromStruct and Bind.
+//
+//	type Settings struct {
+//		Port    int           `config:"APP_PORT" default:"8080" description:"TCP/IP Port" rules:"required,port"`
+//		Timeout time.Duration `config:"APP_TIMEOUT" default:"30s" description:"Request timeout"`
+//	}
+const (
+	// TagName holds the name of the configuration Variable. Fields without it (or with "-") are skipped.
+	TagName = "config"
+
+	// TagDefault holds the DefaultValue of the Variable.
+	TagDefault = "default"
+
+	// TagDescription holds the Description of the Variable.
+	TagDescription = "description"
+
+	// TagRules holds a comma separated list of rule names registered with RegisterRule.
+	TagRules = "rules"
+
+	// TagSensitive marks the Variable as Sensitive if it holds a true value, like sensitive:"true".
+	TagSensitive = "sensitive"
+
+	// TagInterpolate enables the Interpolate of the Variable if it holds a true value, like interpolate:"true".
+	TagInterpolate = "interpolate"
+
+	// TagEnvDefaults holds a comma separated list of EnvDefaults, like env-defaults:"production=warn,dev=debug".
+	TagEnvDefaults = "env-defaults"
+)
+
+// Names of the rules which are added automatically by VariablesFromStruct based on the type of the field.
+const (
+	RuleInteger  = "Integer"
+	RuleBoolean  = "Boolean"
+	RuleFloat    = "Float"
+	RuleDuration = "Duration"
+	RuleURL      = "URL"
+)
+
+// namedRules are the rules which can be referenced in the rules struct tag.
+var namedRules = map[string]validation.Rule{
+	"required":    validation.Required,
+	"port":        is.Port,
+	"url":         is.URL,
+	"email":       is.EmailFormat,
+	"host":        is.Host,
+	"ip":          is.IP,
+	"truthy":      validation.In(constants.TruthyValues...),
+	"environment": validation.In(constants.ValidEnvironments...),
+	"log-level":   validation.In(constants.ValidLogLevels...),
+	"ssl-mode":    validation.In(constants.ValidSSLModes...),
+}
+
+// RegisterRule makes the supplied rule available under name in the rules struct tag.
+// It is not safe for concurrent use, call it from init functions or before VariablesFromStruct.
+func RegisterRule(name string, rule validation.Rule) {
+	namedRules[name] = rule
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(url.URL{})
+	urlPtrType   = reflect.TypeOf(&url.URL{})
+)
+
+// typeRule returns the name and the rule which checks that a string value can be parsed into the type t.
+// Empty values are always valid, use the required rule to enforce a value.
+func typeRule(t reflect.Type) (string, validation.Rule) {
+	var name string
+	var parse func(string) error
+	switch {
+	case t == durationType:
+		name, parse = RuleDuration, func(s string) error { _, err := time.ParseDuration(s); return err }
+	case t == urlType || t == urlPtrType:
+		name, parse = RuleURL, func(s string) error { _, err := parseAbsoluteURL(s); return err }
+	default:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			name, parse = RuleInteger, func(s string) error { _, err := strconv.ParseInt(s, 10, t.Bits()); return err }
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			name, parse = RuleInteger, func(s string) error { _, err := strconv.ParseUint(s, 10, t.Bits()); return err }
+		case reflect.Bool:
+			name, parse = RuleBoolean, func(s string) error { _, err := strconv.ParseBool(s); return err }
+		case reflect.Float32, reflect.Float64:
+			name, parse = RuleFloat, func(s string) error { _, err := strconv.ParseFloat(s, t.Bits()); return err }
+		default:
+			return "", nil
+		}
+	}
+	return name, validation.By(func(value interface{}) error {
+		s, _ := value.(string)
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		if err := parse(strings.TrimSpace(s)); err != nil {
+			return validation.NewError("validation_"+strings.ToLower(name), "must be a valid "+strings.ToLower(name))
+		}
+		return nil
+	})
+}
+
+// isSupportedType reports if a field of type t can be bound to a configuration Variable.
+func isSupportedType(t reflect.Type) bool {
+	if t == durationType || t == urlType || t == urlPtrType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem() == reflect.TypeOf("")
+	}
+	return false
+}
+
+// boundField is a struct field tagged with a configuration Variable name.
+type boundField struct {
+	name  string
+	field reflect.StructField
+	value reflect.Value
+}
+
+// collectFields walks the struct value (and its nested, untagged structs) and returns the tagged fields.
+func collectFields(v reflect.Value) ([]boundField, error) {
+	fields := []boundField{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		name, tagged := field.Tag.Lookup(TagName)
+		if name == "-" {
+			continue
+		}
+		if !tagged {
+			if field.Type.Kind() == reflect.Struct && field.Type != urlType {
+				nested, err := collectFields(v.Field(i))
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, nested...)
+			}
+			continue
+		}
+		if !isSupportedType(field.Type) {
+			return nil, errors.Errorf("Field %s (%s) has unsupported type %s", field.Name, name, field.Type)
+		}
+		fields = append(fields, boundField{name: name, field: field, value: v.Field(i)})
+	}
+	return fields, nil
+}
+
+// structValue dereferences spec and checks that it is a struct.
+func structValue(spec interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(spec)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, errors.New("Nil pointer supplied instead of a struct")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, errors.Errorf("Expected a struct, got %s", v.Kind())
+	}
+	return v, nil
+}
+
+// VariablesFromStruct creates the configuration Variables from the tags of the supplied struct (or pointer to struct).
+// The result can be passed to NewConfig, so the struct is the single source of the defaults,
+// descriptions and rules. Non-string fields get a rule validating that the value can be parsed into their type.
+// It returns an error if a tag references an unknown rule, a name is used twice or a field type is unsupported.
+func VariablesFromStruct(spec interface{}) (map[string]*Variable, error) {
+	v, err := structValue(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Variables from struct")
+	}
+	fields, err := collectFields(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Variables from struct")
+	}
+
+	vars := make(map[string]*Variable, len(fields))
+	for _, f := range fields {
+		if _, ok := vars[f.name]; ok {
+			return nil, errors.Errorf("Configuration variable %s is declared more than once", f.name)
+		}
+		variable := &Variable{
+			DefaultValue: f.field.Tag.Get(TagDefault),
+			Description:  f.field.Tag.Get(TagDescription),
+			Rules:        map[string]validation.Rule{},
+		}
+		if sensitive := f.field.Tag.Get(TagSensitive); sensitive != "" {
+			var err error
+			if variable.Sensitive, err = strconv.ParseBool(sensitive); err != nil {
+				return nil, errors.Wrapf(err, "Invalid sensitive tag on configuration variable %s", f.name)
+			}
+		}
+		if interpolate := f.field.Tag.Get(TagInterpolate); interpolate != "" {
+			var err error
+			if variable.Interpolate, err = strconv.ParseBool(interpolate); err != nil {
+				return nil, errors.Wrapf(err, "Invalid interpolate tag on configuration variable %s", f.name)
+			}
+		}
+		if envDefaults := f.field.Tag.Get(TagEnvDefaults); envDefaults != "" {
+			variable.EnvDefaults = map[string]string{}
+			for _, pair := range splitList(envDefaults) {
+				parts := strings.SplitN(pair, "=", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					return nil, errors.Errorf("Invalid env-defaults tag on configuration variable %s: %s", f.name, pair)
+				}
+				variable.EnvDefaults[parts[0]] = parts[1]
+			}
+		}
+		if ruleName, rule := typeRule(f.field.Type); rule != nil {
+			variable.Rules[ruleName] = rule
+		}
+		for _, ruleName := range splitList(f.field.Tag.Get(TagRules)) {
+			rule, ok := namedRules[ruleName]
+			if !ok {
+				return nil, errors.Errorf("Unknown rule %s on configuration variable %s", ruleName, f.name)
+			}
+			variable.Rules[ruleName] = rule
+		}
+		vars[f.name] = variable
+	}
+	return vars, nil
+}
+
+// NewConfigFromStruct creates a new AppConfig with the Variables declared by the tags of the supplied struct.
+func NewConfigFromStruct(spec interface{}) (*AppConfig, error) {
+	vars, err := VariablesFromStruct(spec)
+	if err != nil {
+		return nil, err
+	}
+	return NewConfig(vars), nil
+}
+
+// Bind fills the tagged fields of the struct pointed by target with the values of the registered Variables.
+// Empty values set the field to its zero value. It returns validation.Errors keyed by the variable names
+// if any value cannot be parsed into the type of its field, or an error if target is not a pointer to a struct
+// or references an unregistered Variable.
+func (appConf *AppConfig) Bind(target interface{}) error {
+	if rv := reflect.ValueOf(target); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("Failed to bind configuration: target must be a non-nil pointer to a struct")
+	}
+	v, err := structValue(target)
+	if err != nil {
+		return errors.Wrap(err, "Failed to bind configuration")
+	}
+	fields, err := collectFields(v)
+	if err != nil {
+		return errors.Wrap(err, "Failed to bind configuration")
+	}
+
+	bindErrors := validation.Errors{}
+	for _, f := range fields {
+		confVar, err := appConf.lookupRegistered(f.name)
+		if err != nil {
+			return errors.Wrap(err, "Failed to bind configuration")
+		}
+		if err := setField(f.value, strings.TrimSpace(confVar.Value)); err != nil {
+			if confVar.secret() {
+				err = errors.Errorf("invalid value %s", MaskedValue)
+			}
+			bindErrors[f.name] = err
+		}
+	}
+	if len(bindErrors) > 0 {
+		return bindErrors.Filter()
+	}
+	return nil
+}
+
+// setField parses raw into the type of the field and sets it.
+func setField(field reflect.Value, raw string) error {
+	t := field.Type()
+	if raw == "" && t.Kind() != reflect.String {
+		field.Set(reflect.Zero(t))
+		return nil
+	}
+	switch {
+	case t == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case t == urlType || t == urlPtrType:
+		u, err := parseAbsoluteURL(raw)
+		if err != nil {
+			return err
+		}
+		if t == urlPtrType {
+			field.Set(reflect.ValueOf(u))
+		} else {
+			field.Set(reflect.ValueOf(*u))
+		}
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(splitList(raw)))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"net/url"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type testNestedSettings struct {
+	Tags []string `config:"TEST_TAGS" default:"a, b,,c" description:"List of tags"`
+}
+
+type testSettings struct {
+	Port     int           `config:"TEST_PORT" default:"8080" description:"TCP/IP Port" rules:"required,port"`
+	Debug    bool          `config:"TEST_DEBUG" default:"true" description:"Debug mode"`
+	Ratio    float64       `config:"TEST_RATIO" default:"0.5" description:"Sampling ratio"`
+	Timeout  time.Duration `config:"TEST_TIMEOUT" default:"1m30s" description:"Request timeout"`
+	Endpoint *url.URL      `config:"TEST_ENDPOINT" default:"https://example.com/api" description:"API endpoint" interpolate:"true"`
+	Name     string        `config:"TEST_NAME" description:"Name of the service"`
+	Token    string        `config:"TEST_TOKEN" description:"API token" sensitive:"true"`
+	Ignored  string        `config:"-"`
+	Nested   testNestedSettings
+}
+
+func (cts *ConfigTestSuite) TestVariablesFromStruct() {
+	vars, err := VariablesFromStruct(testSettings{})
+	cts.NoError(err, "Variables should have been created from the struct")
+	cts.Len(vars, 8, "All tagged fields, including the nested ones, should be registered")
+	cts.Equal("8080", vars["TEST_PORT"].DefaultValue)
+	cts.Equal("TCP/IP Port", vars["TEST_PORT"].Description)
+	cts.Contains(vars["TEST_PORT"].Rules, "required")
+	cts.Contains(vars["TEST_PORT"].Rules, "port")
+	cts.Contains(vars["TEST_PORT"].Rules, RuleInteger)
+	cts.Contains(vars["TEST_TIMEOUT"].Rules, RuleDuration)
+	cts.Contains(vars["TEST_ENDPOINT"].Rules, RuleURL)
+	cts.Empty(vars["TEST_NAME"].Rules, "String fields should not get a type rule")
+	cts.True(vars["TEST_TOKEN"].Sensitive, "The sensitive tag should have been applied")
+	cts.False(vars["TEST_NAME"].Sensitive)
+	cts.True(vars["TEST_ENDPOINT"].Interpolate, "The interpolate tag should have been applied")
+	cts.False(vars["TEST_TOKEN"].Interpolate)
+
+	_, err = VariablesFromStruct(struct {
+		A string `config:"A" rules:"no-such-rule"`
+	}{})
+	cts.EqualError(err, "Unknown rule no-such-rule on configuration variable A")
+
+	_, err = VariablesFromStruct(struct {
+		A string `config:"A"`
+		B string `config:"A"`
+	}{})
+	cts.EqualError(err, "Configuration variable A is declared more than once")
+
+	_, err = VariablesFromStruct(struct {
+		A map[string]string `config:"A"`
+	}{})
+	cts.Error(err, "Map fields should not be supported")
+
+	_, err = VariablesFromStruct("not a struct")
+	cts.Error(err, "Only structs should be accepted")
+}
+
+func (cts *ConfigTestSuite) TestBind() {
+	conf, err := NewConfigFromStruct(&testSettings{})
+	cts.NoError(err, "AppConfig should have been created from the struct")
+	cts.NoError(conf.loadEnv(), "Defaults should have been loaded")
+	cts.NoError(conf.Validate(), "Defaults should be valid")
+	cts.Contains(conf.DumpTable(), "Request timeout", "Descriptions should be in the table")
+
+	settings := testSettings{Ignored: "untouched"}
+	cts.NoError(conf.Bind(&settings), "Configuration should have been bound")
+	cts.Equal(8080, settings.Port)
+	cts.True(settings.Debug)
+	cts.Equal(0.5, settings.Ratio)
+	cts.Equal(90*time.Second, settings.Timeout)
+	cts.Equal("https://example.com/api", settings.Endpoint.String())
+	cts.Equal("", settings.Name)
+	cts.Equal("untouched", settings.Ignored)
+	cts.Equal([]string{"a", "b", "c"}, settings.Nested.Tags)
+
+	conf.vars["TEST_PORT"].Value = "eighty"
+	conf.vars["TEST_TIMEOUT"].Value = "soon"
+	errs := conf.Validate()
+	cts.Error(errs, "Type rules should fail on unparsable values")
+	cts.Contains(errs.Error(), "must be a valid integer")
+	cts.Contains(errs.Error(), "must be a valid duration")
+
+	err = conf.Bind(&settings)
+	cts.IsType(validation.Errors{}, err, "Bind errors should be validation.Errors")
+	cts.Contains(err.(validation.Errors), "TEST_PORT")
+	cts.Contains(err.(validation.Errors), "TEST_TIMEOUT")
+
+	cts.Error(conf.Bind(settings), "Bind should require a pointer")
+	cts.Error(NewConfig(nil).Bind(&settings), "Bind should fail on unregistered variables")
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+
+	"github.com/toolbox/cli"
+)
+
+// DefaultSampleFile is the file written by the sample subcommand if no filename is supplied.
+const DefaultSampleFile = ".env.sample"
+
+// ConfigCLI is a wrapper used to generate a CLI interface for an AppConfig,
+// to check, compare and document the configuration of an application.
+type ConfigCLI struct {
+	config *AppConfig
+	output io.Writer
+}
+
+// ConfigCLIOptions are the possible options for the NewConfigCLI to create a ConfigCLI.
+type ConfigCLIOptions struct {
+	// Config is the AppConfig with the registered Variables (the schema).
+	Config *AppConfig
+
+	// Output is where the reports and tables are printed. Defaults to os.Stdout.
+	Output io.Writer
+}
+
+// NewConfigCLI creates a new ConfigCLI with the supplied ConfigCLIOptions
+func NewConfigCLI(opts ConfigCLIOptions) *ConfigCLI {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	return &ConfigCLI{
+		config: opts.Config,
+		output: opts.Output,
+	}
+}
+
+// BuildConfigCommand builds the config toolbox/cli command. Which is a command line interface of the AppConfig.
+func (ccli *ConfigCLI) BuildConfigCommand() *cli.Command {
+	return cli.NewCommand("config").
+		WithAliases("conf", "cfg").
+		WithShort("Check, compare and document the configuration").
+		WithLong(`This utility command is used to check and document the configuration of the application.
+The target configuration is read from the supplied envfile(s), or from the environment if no file is supplied.`).
+		WithExamples("./app config check .env.production").
+		WithOutput(ccli.output).
+		WithRun(cli.EndWithHelp).
+		WithSubCommands(
+			ccli.configCheckCommand(),
+			ccli.configDiffCommand(),
+			ccli.configSampleCommand(),
+			ccli.configTableCommand(),
+		)
+}
+
+// targetSources returns the Sources of the target configuration: the envfile(s) in args, or the environment.
+func targetSources(args []string) []Source {
+	if len(args) > 0 {
+		return []Source{NewEnvFileSource(args...)}
+	}
+	return []Source{NewEnvSource()}
+}
+
+func (ccli *ConfigCLI) configCheckCommand() *cli.Command {
+	return cli.NewCommand("check").
+		WithAliases("validate", "lint").
+		WithShort("Validates the target configuration, fails on missing, unknown or invalid variables").
+		WithTask(func(args []string) error {
+			report, err := ccli.config.Drift(targetSources(args)...)
+			if err != nil {
+				return errors.Wrap(err, "Failed to check configuration")
+			}
+			for _, entry := range report.Entries {
+				if entry.Status == StatusMissing || entry.Status == StatusUnknown || entry.Status == StatusInvalid {
+					fmt.Fprintf(ccli.output, "%-8s %s\n", entry.Status, entry.Name)
+				}
+			}
+			if len(report.Errors) > 0 {
+				fmt.Fprintf(ccli.output, "\n%s\n", report.Errors.Error())
+			}
+			if failed := report.Failed(); failed > 0 {
+				return errors.Errorf(
+					"Configuration check failed: %d missing, %d unknown, %d invalid variable(s)",
+					report.Count(StatusMissing), report.Count(StatusUnknown), report.Count(StatusInvalid),
+				)
+			}
+			fmt.Fprintln(ccli.output, "Configuration is valid")
+			return nil
+		})
+}
+
+func (ccli *ConfigCLI) configDiffCommand() *cli.Command {
+	return cli.NewCommand("diff").
+		WithAliases("drift").
+		WithShort("Prints the variables of the target configuration which differ from the defaults").
+		WithTask(func(args []string) error {
+			report, err := ccli.config.Drift(targetSources(args)...)
+			if err != nil {
+				return errors.Wrap(err, "Failed to compare configuration")
+			}
+			fmt.Fprint(ccli.output, renderDriftReport(report))
+			if failed := report.Failed(); failed > 0 {
+				return errors.Errorf("Configuration drift: %d variable(s) are missing, unknown or invalid", failed)
+			}
+			return nil
+		})
+}
+
+func (ccli *ConfigCLI) configSampleCommand() *cli.Command {
+	return cli.NewCommand("sample").
+		WithAliases("gen").
+		WithShort("Creates the sample envfile (default: .env.sample)").
+		WithTask(func(args []string) error {
+			filename := DefaultSampleFile
+			if len(args) > 0 {
+				filename = args[0]
+			}
+			if err := ccli.config.CreateSampleFile(filename); err != nil {
+				return errors.Wrap(err, "Failed to create sample file")
+			}
+			fmt.Fprintf(ccli.output, "Sample file created: %s\n", filename)
+			return nil
+		})
+}
+
+func (ccli *ConfigCLI) configTableCommand() *cli.Command {
+	return cli.NewCommand("table").
+		WithAliases("list", "ls").
+		WithShort("Prints the variables with descriptions, constraints and default values").
+		WithTask(func(args []string) error {
+			fmt.Fprint(ccli.output, ccli.config.DumpTable())
+			return nil
+		})
+}
+
+// renderDriftReport renders the DriftReport as a table.
+func renderDriftReport(report *DriftReport) string {
+	data := [][]string{}
+	for _, entry := range report.Entries {
+		data = append(data, []string{entry.Name, entry.Status, entry.DefaultValue, entry.Value})
+	}
+
+	tableString := &strings.Builder{}
+	table := tablewriter.NewWriter(tableString)
+	table.SetHeader([]string{"Variable Name", "Status", "Default Value", "Value"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetRowLine(true)
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.AppendBulk(data)
+	table.Render()
+
+	tableString.WriteString(fmt.Sprintf("\n%d changed, %d missing, %d unknown, %d invalid\n",
+		report.Count(StatusChanged),
+		report.Count(StatusMissing),
+		report.Count(StatusUnknown),
+		report.Count(StatusInvalid),
+	))
+	return tableString.String()
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/toolbox/constants"
+)
+
+func (cts *ConfigTestSuite) configCLITestConfig() *AppConfig {
+	return NewConfig(map[string]*Variable{
+		"CLI_PORT": {
+			DefaultValue: "8080",
+			Description:  "TCP/IP Port",
+			Rules:        map[string]validation.Rule{"Valid port": is.Port},
+		},
+		"CLI_SECRET": {
+			Description: "API secret",
+			Sensitive:   true,
+			Rules:       map[string]validation.Rule{"Required": validation.Required},
+		},
+		"CLI_LEVEL": {
+			DefaultValue: "info",
+		},
+	})
+}
+
+func (cts *ConfigTestSuite) TestDrift() {
+	envFile := cts.writeTempFile("config-cli-*.env", "CLI_PORT=not-a-port\nCLI_LEVEL=debug\nCLI_TYPO=1\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+
+	report, err := cts.configCLITestConfig().Drift(NewEnvFileSource(envFile))
+	cts.NoError(err, "Drift report should have been created")
+	cts.Equal([]DriftEntry{
+		{Name: "CLI_LEVEL", Status: StatusChanged, DefaultValue: "info", Value: "debug"},
+		{Name: "CLI_PORT", Status: StatusInvalid, DefaultValue: "8080", Value: "not-a-port"},
+		{Name: "CLI_SECRET", Status: StatusMissing},
+		{Name: "CLI_TYPO", Status: StatusUnknown},
+	}, report.Entries)
+	cts.Equal(3, report.Failed())
+	cts.Len(report.Errors, 2)
+
+	_, err = cts.configCLITestConfig().Drift(NewEnvFileSource("no-such-file"))
+	cts.Error(err, "Drift should fail on missing envfile")
+}
+
+func (cts *ConfigTestSuite) TestDriftResolvedDefaults() {
+	first := cts.writeTempFile("config-cli-*.env", "CLI_TYPO=1\n")
+	second := cts.writeTempFile("config-cli-*.env", "CLI_TYPO=2\n")
+	defer func() {
+		for _, f := range []string{first, second} {
+			cts.NoError(os.Remove(f), "Temp file should have been removed")
+		}
+	}()
+
+	conf := NewConfig(map[string]*Variable{
+		"APP_ENV":  {DefaultValue: "production"},
+		"CLI_HOST": {DefaultValue: "localhost"},
+		"CLI_URL":  {DefaultValue: "http://${CLI_HOST}:8080", Interpolate: true},
+		"CLI_PORT": {DefaultValue: "8080", EnvDefaults: map[string]string{"production": "80"}},
+	})
+	report, err := conf.Drift(NewEnvFileSource(first), NewEnvFileSource(second))
+	cts.NoError(err, "Drift report should have been created")
+	cts.Equal([]DriftEntry{
+		{Name: "APP_ENV", Status: StatusUnchanged, DefaultValue: "production", Value: "production"},
+		{Name: "CLI_HOST", Status: StatusUnchanged, DefaultValue: "localhost", Value: "localhost"},
+		{Name: "CLI_PORT", Status: StatusUnchanged, DefaultValue: "80", Value: "80"},
+		{Name: "CLI_TYPO", Status: StatusUnknown},
+		{Name: "CLI_URL", Status: StatusUnchanged, DefaultValue: "http://localhost:8080", Value: "http://localhost:8080"},
+	}, report.Entries, "Resolved defaults should not drift and unknown variables should be listed once")
+}
+
+func (cts *ConfigTestSuite) TestDriftProfileEnvfile() {
+	envFile := cts.writeTempFile("config-cli-*.env", "APP_ENV=production\n")
+	profileFile := envFile + "." + constants.ENV_PRODUCTION
+	cts.NoError(ioutil.WriteFile(profileFile, []byte("CLI_SECRET=top-secret\n"), 0600))
+	defer func() {
+		for _, f := range []string{envFile, profileFile} {
+			cts.NoError(os.Remove(f), "Temp file should have been removed")
+		}
+	}()
+
+	conf := NewConfig(map[string]*Variable{
+		constants.APP_ENV: {DefaultValue: constants.ENV_DEV},
+		"CLI_SECRET":      {Rules: map[string]validation.Rule{"Required": validation.Required}},
+	})
+	report, err := conf.Drift(NewEnvFileSource(envFile))
+	cts.NoError(err, "Drift report should have been created")
+	cts.Equal([]DriftEntry{
+		{Name: constants.APP_ENV, Status: StatusChanged, DefaultValue: constants.ENV_DEV, Value: constants.ENV_PRODUCTION},
+		{Name: "CLI_SECRET", Status: StatusChanged, Value: "top-secret"},
+	}, report.Entries, "The variables of the profile envfile should not be missing")
+}
+
+func (cts *ConfigTestSuite) TestConfigCommand() {
+	invalidFile := cts.writeTempFile("config-cli-*.env", "CLI_PORT=not-a-port\nCLI_TYPO=1\n")
+	validFile := cts.writeTempFile("config-cli-*.env", "CLI_PORT=9090\nCLI_SECRET=top-secret\n")
+	sampleFile := cts.writeTempFile("config-cli-*.sample", "")
+	defer func() {
+		for _, f := range []string{invalidFile, validFile, sampleFile} {
+			cts.NoError(os.Remove(f), "Temp file should have been removed")
+		}
+	}()
+
+	output := &bytes.Buffer{}
+	cmd := NewConfigCLI(ConfigCLIOptions{Config: cts.configCLITestConfig(), Output: output}).BuildConfigCommand()
+	cts.NoError(cmd.Validate(), "The subcommands should not share names or aliases")
+
+	err := cmd.Execute([]string{"check", invalidFile})
+	cts.EqualError(err, "Configuration check failed: 1 missing, 1 unknown, 1 invalid variable(s)")
+	cts.Contains(output.String(), "unknown  CLI_TYPO")
+	cts.Contains(output.String(), "CLI_PORT = not-a-port: (Valid port: must be a valid port number.)")
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"check", validFile}), "Valid configuration should pass the check")
+	cts.Contains(output.String(), "Configuration is valid")
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"diff", validFile}), "Diff of valid configuration should not fail")
+	cts.Contains(output.String(), "2 changed, 0 missing, 0 unknown, 0 invalid")
+	cts.Contains(output.String(), MaskedValue)
+	cts.NotContains(output.String(), "top-secret", "Sensitive values should be masked")
+	cts.Error(cmd.Execute([]string{"diff", invalidFile}), "Diff should fail on drift")
+
+	output.Reset()
+	cts.NoError(cmd.Execute([]string{"table"}), "Table should have been printed")
+	cts.Contains(output.String(), "API secret")
+
+	cts.NoError(cmd.Execute([]string{"sample", sampleFile}), "Sample file should have been created")
+	content, err := ioutil.ReadFile(sampleFile)
+	cts.NoError(err)
+	cts.Contains(string(content), "CLI_PORT=8080")
+
+	cts.EqualError(cmd.Execute([]string{"nope"}), "Invalid Command: nope")
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/joho/godotenv"
@@ -32,11 +33,75 @@ type Variable struct {
 
 	// Rules are a map of named validation.Rules that should apply to the Variable's Value.
 	Rules map[string]validation.Rule
+
+	// Origin is the name of the Source the Value was loaded from (OriginDefault if no Source set it).
+	Origin string
+
+	// EnvDefaults are overlays of the DefaultValue by environment (the value of APP_ENV),
+	// like {"production": "warn", "dev": "debug"}.
+	EnvDefaults map[string]string
+
+	// Sensitive marks the Variable as secret (password, token, etc.), so its values are masked
+	// in DumpTable, Dump, CreateSampleFile and the validation errors.
+	// The values of the Variables interpolating it are masked too.
+	Sensitive bool
+
+	// Interpolate enables the ${NAME} references (with $$ escaping a $) and the file: references in the Value
+	// and the defaults of the Variable. Without it the values are taken literally, so secrets and URLs can hold
+	// any $ sequence. The FileSuffix convention works either way.
+	Interpolate bool
+
+	// fileReference marks the Value as a file reference set by the FileSuffix convention.
+	fileReference bool
+
+	// sensitiveReference marks the Value as containing the value of a Sensitive Variable it references.
+	sensitiveReference bool
+
+	// resolveErr is the failure of the interpolation or the file reference of the Value.
+	resolveErr error
+}
+
+// MaskedValue replaces the non-empty values of Sensitive Variables in every rendering.
+const MaskedValue = "******"
+
+// mask returns MaskedValue instead of a non-empty sensitive value.
+func mask(sensitive bool, val string) string {
+	if sensitive && val != "" {
+		return MaskedValue
+	}
+	return val
+}
+
+// secret reports if the Value of the Variable is secret: the Variable is Sensitive, or its Value
+// references a Sensitive Variable.
+func (v *Variable) secret() bool {
+	return v.Sensitive || v.sensitiveReference
+}
+
+// DisplayValue returns the Value of the Variable, masked if the Variable is Sensitive
+// or its Value references a Sensitive Variable.
+func (v *Variable) DisplayValue() string {
+	return mask(v.secret(), v.Value)
+}
+
+// DisplayDefaultValue returns the DefaultValue of the Variable, masked if the Variable is Sensitive.
+func (v *Variable) DisplayDefaultValue() string {
+	return mask(v.Sensitive, v.DefaultValue)
 }
 
 // AppConfig is the collection of application configuration items of an application.
+// It is safe for concurrent use, values can be read while the AppConfig is reloaded.
 type AppConfig struct {
-	vars map[string]*Variable
+	mu          sync.RWMutex
+	vars        map[string]*Variable
+	sources     []Source
+	rules       map[string]CrossRule
+	isolated    bool
+	envfiles    []string
+	fileStates  map[string]fileState
+	subscribers map[string]map[int]func(Change)
+	lastSubID   int
+	overloaded  map[string]envValue
 }
 
 // NewConfig creates a new AppConfig with the supplied default Variables.
@@ -52,6 +117,7 @@ func NewConfig(defaults map[string]*Variable) *AppConfig {
 
 // Setup the Application's Configuration according to the defaults, environment variables and the envfile(s).
 // If no env file supplied, only the defaults and environment variables will be checked.
+// With Sources the envfile(s) take precedence over every Source, see WithSources.
 // Return an error if the config file(s) cannot be loaded, or the configurations are invalid.
 // On invalid configs the returned error will be the type validation.Errors.
 func (appConf *AppConfig) Setup(envfiles ...string) error {
@@ -64,6 +130,8 @@ func (appConf *AppConfig) Setup(envfiles ...string) error {
 // Lookup returns the named Application Configuration Variable's value (or an empty string),
 // and a boolean indicating if it was find or not.
 func (appConf *AppConfig) Lookup(name string) (string, bool) {
+	appConf.mu.RLock()
+	defer appConf.mu.RUnlock()
 	if val, ok := appConf.vars[name]; ok {
 		return val.Value, true
 	}
@@ -76,15 +144,27 @@ func (appConf *AppConfig) Get(name string) string {
 	return val
 }
 
-// ValidationErrors applies on each Variable its own validation rules, unifies the errors and returns them.
+// ValidationErrors applies on each Variable its own validation rules, then the CrossRules of the AppConfig,
+// unifies the errors and returns them.
 func (appConf *AppConfig) ValidationErrors() validation.Errors {
+	appConf.mu.RLock()
+	defer appConf.mu.RUnlock()
+	return appConf.validate(appConf.vars)
+}
+
+// validateVars applies on each Variable its own validation rules, unifies the errors and returns them.
+func validateVars(vars map[string]*Variable) validation.Errors {
 	// allErrors collects all validation errors
 	allErrors := validation.Errors{}
 
 	// iterate over variables
-	for confKey, confVar := range appConf.vars {
+	for confKey, confVar := range vars {
 		// validationErrors collects all validation error associated with one variable
 		validationErrors := validation.Errors{}
+		// values which cannot be resolved are invalid
+		if confVar.resolveErr != nil {
+			validationErrors[RuleReference] = confVar.resolveErr
+		}
 		// iterate over rules
 		for ruleName, rule := range confVar.Rules {
 			// call the rule on the value and collect errors
@@ -94,7 +174,7 @@ func (appConf *AppConfig) ValidationErrors() validation.Errors {
 		}
 		// if there were any validation error add them to the top level collection
 		if len(validationErrors) > 0 {
-			allErrors[fmt.Sprintf("%s = %s", confKey, confVar.Value)] = validationErrors.Filter()
+			allErrors[fmt.Sprintf("%s = %s", confKey, confVar.DisplayValue())] = validationErrors.Filter()
 		}
 	}
 
@@ -114,28 +194,106 @@ func (appConf *AppConfig) Validate() error {
 	return nil
 }
 
-// loadEnv loads variables from the envfile(s) and the environment, into the AppConfig.
-// Variables in the envfile(s) takes precedence over environment variables.
+// loadEnv loads variables from the configured Sources into the AppConfig.
+// Without Sources the variables are loaded from the envfile(s) and the environment,
+// where variables in the envfile(s) takes precedence over environment variables.
+// With Sources the envfile(s) are loaded by an EnvFileSource after all the other Sources.
+// Every envfile is followed by its profile envfile (.env.<APP_ENV>) if it exists.
 func (appConf *AppConfig) loadEnv(envfiles ...string) error {
-	// If any env file is provided try load it.
+	states := statFiles(appConf.watchedFiles(envfiles))
+	sources, loaded, err := appConf.load(envfiles...)
+	if err != nil {
+		return err
+	}
+
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	appConf.envfiles = envfiles
+	appConf.fileStates = states
+	applyValues(appConf.vars, sources, loaded)
+	return nil
+}
+
+// sourcesFor returns the configured Sources completed with the envfile(s).
+// Without configured Sources the envfile(s) overload the process environment first, unless the AppConfig is Isolated.
+func (appConf *AppConfig) sourcesFor(envfiles ...string) ([]Source, error) {
+	appConf.mu.RLock()
+	sources := appConf.sources
+	isolated := appConf.isolated
+	appConf.mu.RUnlock()
+	if len(sources) == 0 {
+		if !isolated {
+			// The variables removed from the envfile(s) since the last load are removed from the environment too
+			appConf.restoreEnv()
+		}
+		// If any env file is provided try load it.
+		if len(envfiles) > 0 && !isolated {
+			// Overload existing environment variables with the ones in the envfile(s).
+			if err := appConf.overloadEnv(envfiles...); err != nil {
+				return nil, errors.Wrap(err, "Failed to overload variables with envfile(s)")
+			}
+		}
+		sources = []Source{NewEnvSource()}
+	}
 	if len(envfiles) > 0 {
-		// Overload existing environment variables with the ones in the envfile(s).
-		if err := godotenv.Overload(envfiles...); err != nil {
-			return errors.Wrap(err, "Failed to overload variables with envfile(s)")
+		// The envfile source is also added without custom Sources, so DumpTable shows the correct origin
+		sources = append(sources[:len(sources):len(sources)], NewEnvFileSource(envfiles...))
+	}
+	return sources, nil
+}
+
+// envValue is the value of an environment variable before it was overloaded by an envfile.
+type envValue struct {
+	value string
+	set   bool
+}
+
+// overloadEnv overloads the environment variables with the ones in the envfile(s), and keeps the original values
+// of the overloaded variables, so they can be restored by restoreEnv.
+func (appConf *AppConfig) overloadEnv(envfiles ...string) error {
+	values, err := godotenv.Read(envfiles...)
+	if err != nil {
+		return err
+	}
+	appConf.mu.Lock()
+	if appConf.overloaded == nil {
+		appConf.overloaded = map[string]envValue{}
+	}
+	for key := range values {
+		if _, ok := appConf.overloaded[key]; !ok {
+			value, set := os.LookupEnv(key)
+			appConf.overloaded[key] = envValue{value: value, set: set}
 		}
 	}
+	appConf.mu.Unlock()
+	return godotenv.Overload(envfiles...)
+}
 
-	// Iterate over all Variables
-	for confKey, confVar := range appConf.vars {
-		// Set default
-		appConf.vars[confKey].Value = confVar.DefaultValue
-		// Check in environment
-		if val := os.Getenv(confKey); val != "" {
-			appConf.vars[confKey].Value = val
+// restoreEnv restores the environment variables overloaded by the envfile(s) to their original values,
+// so the variables removed from the envfile(s) are not loaded again from the environment.
+func (appConf *AppConfig) restoreEnv() {
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	for key, original := range appConf.overloaded {
+		if original.set {
+			_ = os.Setenv(key, original.value)
+		} else {
+			_ = os.Unsetenv(key)
 		}
 	}
+	appConf.overloaded = nil
+}
 
-	return nil
+// keys returns the names of the registered Variables in alphabetic order.
+// The caller must hold the lock of the AppConfig.
+func (appConf *AppConfig) keys() []string {
+	keys := make([]string, 0, len(appConf.vars))
+	for key := range appConf.vars {
+		keys = append(keys, key)
+	}
+	// Sort is needed because maps always return values in random order
+	sort.Strings(keys)
+	return keys
 }
 
 /////////////////////////////////////////
@@ -229,33 +387,22 @@ func (appConf *AppConfig) Address() string {
 }
 
 // DumpTable creates a string table with all the config variable names,
-// descriptions, constraints and default values
+// descriptions, constraints, default values and the source of the actual values
 func (appConf *AppConfig) DumpTable() string {
+	appConf.mu.RLock()
 	// Add the config variables to data in alphabetic order
 	data := [][]string{}
-	keys := []string{}
-	for key := range appConf.vars {
-		keys = append(keys, key)
-	}
-	// Sort is needed because maps always return values in random order
-	sort.Strings(keys)
-	for _, key := range keys {
+	for _, key := range appConf.keys() {
 		elem := appConf.vars[key]
-		// Collect constraints
-		constraints := []string{}
-		for rule := range elem.Rules {
-			constraints = append(constraints, rule)
-		}
-		// Sort is needed because maps always return values in random order
-		sort.Strings(constraints)
-		constraintList := strings.Join(constraints, ", ")
-		data = append(data, []string{key, elem.Description, constraintList, elem.DefaultValue})
+		constraintList := strings.Join(appConf.constraints(key), ", ")
+		data = append(data, []string{key, elem.Description, constraintList, elem.displayDefaults(), elem.Origin})
 	}
+	appConf.mu.RUnlock()
 
 	// Create the table
 	tableString := &strings.Builder{}
 	table := tablewriter.NewWriter(tableString)
-	table.SetHeader([]string{"Variable Name", "Description", "Constraints", "Default Value"})
+	table.SetHeader([]string{"Variable Name", "Description", "Constraints", "Default Value", "Source"})
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetRowSeparator("-")
 	table.SetRowLine(true)
@@ -267,29 +414,31 @@ func (appConf *AppConfig) DumpTable() string {
 	return tableString.String()
 }
 
-// CreateSampleFile creates the .env.sample file based on the AppConfig variables with description and constraints.
-func (appConf *AppConfig) CreateSampleFile(filename string) error {
-	// Add the config variables to data in alphabetic order
-	data := [][]string{}
-	keys := []string{}
-	for key := range appConf.vars {
-		keys = append(keys, key)
-	}
-	// Sort is needed because maps always return values in random order
-	sort.Strings(keys)
-	for _, key := range keys {
-		elem := appConf.vars[key]
-		// Collect constraints
-		constraints := []string{}
-		for rule := range elem.Rules {
-			constraints = append(constraints, rule)
-		}
-		// Sort is needed because maps always return values in random order
-		sort.Strings(constraints)
-		constraintList := strings.Join(constraints, ", ")
-		data = append(data, []string{key, elem.DefaultValue, elem.Description, constraintList})
+// FieldLogger can create log entries with additional fields, like logger.Logger.
+type FieldLogger interface {
+	WithFields(fields logrus.Fields) *logrus.Entry
+}
+
+// Dump logs the effective configuration (with the Sensitive values masked) and the origin of the values
+// in a single info level entry. Use it at startup with the application's logger.Logger.
+func (appConf *AppConfig) Dump(log FieldLogger) {
+	appConf.mu.RLock()
+	values := make(map[string]string, len(appConf.vars))
+	origins := make(map[string]string, len(appConf.vars))
+	for key, elem := range appConf.vars {
+		values[key] = elem.DisplayValue()
+		origins[key] = elem.Origin
 	}
+	appConf.mu.RUnlock()
+
+	log.WithFields(logrus.Fields{
+		"config":         values,
+		"config_sources": origins,
+	}).Info("Effective configuration")
+}
 
+// CreateSampleFile creates the .env.sample file based on the AppConfig variables with description and constraints.
+func (appConf *AppConfig) CreateSampleFile(filename string) error {
 	// Open the file for read and write, this will overwrite already existing files
 	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
@@ -304,12 +453,46 @@ func (appConf *AppConfig) CreateSampleFile(filename string) error {
 
 	// Create a buffer
 	datawriter := bufio.NewWriter(file)
+	if err := appConf.writeSample(datawriter); err != nil {
+		return err
+	}
+	// Flush the buffer into the file
+	if err := datawriter.Flush(); err != nil {
+		return errors.Wrapf(err, "Failed to write buffer into %s file", filename)
+	}
+
+	return nil
+}
+
+// sample returns the content of the .env.sample file.
+func (appConf *AppConfig) sample() string {
+	sample := &strings.Builder{}
+	datawriter := bufio.NewWriter(sample)
+	// Writing into a strings.Builder cannot fail
+	_ = appConf.writeSample(datawriter)
+	_ = datawriter.Flush()
+	return sample.String()
+}
+
+// writeSample writes the AppConfig variables with description and constraints into the buffer in .env format.
+func (appConf *AppConfig) writeSample(datawriter *bufio.Writer) error {
+	appConf.mu.RLock()
+	// Add the config variables to data in alphabetic order
+	env := appConf.env()
+	data := [][]string{}
+	for _, key := range appConf.keys() {
+		elem := appConf.vars[key]
+		constraintList := strings.Join(appConf.constraints(key), ", ")
+		data = append(data, []string{key, mask(elem.Sensitive, elem.DefaultFor(env)), elem.Description, constraintList})
+	}
+	appConf.mu.RUnlock()
+
 	if _, err := datawriter.WriteString("# Automatically created by the application from the config object\n\n"); err != nil {
 		return errors.Wrap(err, "Failed to write line into buffer")
 	}
 	for _, elem := range data {
 		// Write description line
-		_, err = datawriter.WriteString(fmt.Sprintf("# Description: %s # Constraints: %s\n", elem[2], elem[3]))
+		_, err := datawriter.WriteString(fmt.Sprintf("# Description: %s # Constraints: %s\n", elem[2], elem[3]))
 		if err != nil {
 			return errors.Wrap(err, "Failed to write line into buffer")
 		}
@@ -319,10 +502,6 @@ func (appConf *AppConfig) CreateSampleFile(filename string) error {
 			return errors.Wrap(err, "Failed to write line into buffer")
 		}
 	}
-	// Flush the buffer into the file
-	if err := datawriter.Flush(); err != nil {
-		return errors.Wrapf(err, "Failed to write buffer into %s file", filename)
-	}
 
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/go-ozzo/ozzo-validation/is"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
 
 	constants "github.com/toolboxconstants"
 
@@ -400,6 +401,60 @@ func (cts *ConfigTestSuite) TestLoadConfig() {
 	}
 }
 
+func (cts *ConfigTestSuite) TestSensitive() {
+	sampleFile := cts.setupEnvTest("APP_DB_PASSWORD")
+	defer func(fileName string) {
+		cts.NoErrorf(os.Remove(fileName), "Temp sampleFile (%s) should have been removed", fileName)
+	}(sampleFile)
+
+	conf := NewConfig(map[string]*Variable{
+		"APP_DB_PASSWORD": {
+			DefaultValue: "default-secret",
+			Description:  "Password of the database",
+			Sensitive:    true,
+			Rules: map[string]validation.Rule{
+				"Length": validation.Length(20, 0),
+			},
+		},
+		"APP_DB_USER": {
+			DefaultValue: "admin",
+		},
+	})
+	cts.setEnvVars(map[string]string{"APP_DB_PASSWORD": "super-secret"})
+	defer func() { cts.NoError(os.Unsetenv("APP_DB_PASSWORD")) }()
+
+	err := conf.Setup()
+	cts.Error(err, "The password should be too short")
+	cts.Contains(err.Error(), "APP_DB_PASSWORD = "+MaskedValue)
+	cts.NotContains(err.Error(), "super-secret", "Validation errors should not contain the sensitive value")
+	cts.Equal("super-secret", conf.Get("APP_DB_PASSWORD"), "Get should return the actual value")
+
+	tab := conf.DumpTable()
+	cts.NotContains(tab, "default-secret", "The table should not contain the sensitive default value")
+	cts.Contains(tab, MaskedValue)
+
+	_, err = conf.GetInt("APP_DB_PASSWORD")
+	cts.EqualError(err, "Failed to parse APP_DB_PASSWORD as integer: invalid value "+MaskedValue)
+
+	cts.NoError(conf.CreateSampleFile(sampleFile), "The sample file should have been created")
+	content, err := ioutil.ReadFile(sampleFile)
+	cts.NoError(err, "The sample file should be readable")
+	cts.Contains(string(content), "APP_DB_PASSWORD="+MaskedValue)
+	cts.Contains(string(content), "APP_DB_USER=admin")
+
+	nullLogger, hook := logrusTest.NewNullLogger()
+	conf.Dump(nullLogger)
+	cts.Equal("Effective configuration", hook.LastEntry().Message)
+	cts.Equal(map[string]string{
+		"APP_DB_PASSWORD": MaskedValue,
+		"APP_DB_USER":     "admin",
+	}, hook.LastEntry().Data["config"], "The sensitive values should be masked in the log")
+	cts.Equal(map[string]string{
+		"APP_DB_PASSWORD": "environment",
+		"APP_DB_USER":     OriginDefault,
+	}, hook.LastEntry().Data["config_sources"])
+}
+
 // TestConfig runs the whole test suite
 func TestConfig(t *testing.T) {
 	suite.Run(t, new(ConfigTestSuite))
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Statuses of the Variables in a DriftReport.
+const (
+	StatusMissing   = "missing"
+	StatusUnknown   = "unknown"
+	StatusInvalid   = "invalid"
+	StatusChanged   = "changed"
+	StatusUnchanged = "default"
+)
+
+// DriftEntry is the state of a single variable in a DriftReport.
+type DriftEntry struct {
+	// Name of the variable.
+	Name string
+
+	// Status is one of the Status* constants.
+	Status string
+
+	// DefaultValue is the (masked if Sensitive) default value of the registered Variable.
+	DefaultValue string
+
+	// Value is the (masked if Sensitive) value in the target.
+	Value string
+}
+
+// DriftReport is the result of comparing a target configuration with the schema of the AppConfig.
+type DriftReport struct {
+	// Entries are the registered and the unknown variables in alphabetic order.
+	Entries []DriftEntry
+
+	// Errors are the validation errors of the target configuration.
+	Errors validation.Errors
+}
+
+// Failed returns the number of missing, unknown and invalid variables.
+func (dr *DriftReport) Failed() int {
+	failed := 0
+	for _, entry := range dr.Entries {
+		switch entry.Status {
+		case StatusMissing, StatusUnknown, StatusInvalid:
+			failed++
+		}
+	}
+	return failed
+}
+
+// Count returns the number of entries with the given status.
+func (dr *DriftReport) Count(status string) int {
+	count := 0
+	for _, entry := range dr.Entries {
+		if entry.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Drift loads the target configuration from the supplied Sources (without touching the AppConfig's values)
+// and compares it with the registered Variables. A Variable is missing if it is required but has no value,
+// invalid if any of its rules (or a CrossRule depending on it) fails and changed if its value differs from
+// its (resolved) default in the target environment.
+// The profile envfile(s) (.env.<APP_ENV>) of the EnvFileSources are loaded the same way as by Setup.
+// The variables of the Sources which are not registered are unknown, except for the process environment.
+func (appConf *AppConfig) Drift(sources ...Source) (*DriftReport, error) {
+	loaded, err := loadValues(sources)
+	if err != nil {
+		return nil, err
+	}
+	sources, loaded, err = appConf.withProfiles(sources, loaded, false)
+	if err != nil {
+		return nil, err
+	}
+
+	appConf.mu.RLock()
+	target := make(map[string]*Variable, len(appConf.vars))
+	for key, confVar := range appConf.vars {
+		varCopy := *confVar
+		target[key] = &varCopy
+	}
+	rules := make(map[string]CrossRule, len(appConf.rules))
+	for ruleName, rule := range appConf.rules {
+		rules[ruleName] = rule
+	}
+	appConf.mu.RUnlock()
+	applyValues(target, sources, loaded)
+	env := resolveEnv(target, loaded)
+	// the defaults are resolved like the values, so the references in the defaults are no changes
+	defaults := make(map[string]*Variable, len(target))
+	for key, confVar := range target {
+		varCopy := *confVar
+		varCopy.Value = confVar.DefaultFor(env)
+		defaults[key] = &varCopy
+	}
+	resolveValues(defaults)
+
+	report := &DriftReport{Entries: []DriftEntry{}, Errors: validation.Errors{}}
+	for key, confVar := range target {
+		entry := DriftEntry{
+			Name:         key,
+			Status:       StatusUnchanged,
+			DefaultValue: defaults[key].DisplayValue(),
+			Value:        confVar.DisplayValue(),
+		}
+		varErrors := validateVars(map[string]*Variable{key: confVar})
+		switch {
+		case len(varErrors) > 0 && confVar.Value == "" && isRequired(confVar):
+			entry.Status = StatusMissing
+		case len(varErrors) > 0:
+			entry.Status = StatusInvalid
+		case confVar.Value != defaults[key].Value:
+			entry.Status = StatusChanged
+		}
+		for errKey, err := range varErrors {
+			report.Errors[errKey] = err
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+
+	// the Variables of the failed CrossRules are invalid too
+	ruleErrors := validateRules(target, rules)
+	for errKey, err := range ruleErrors {
+		report.Errors[errKey] = err
+	}
+	for ruleName, rule := range rules {
+		if !ruleFailed(ruleErrors, rule, ruleName) {
+			continue
+		}
+		for i, entry := range report.Entries {
+			for _, name := range rule.Variables {
+				if entry.Name == name && (entry.Status == StatusChanged || entry.Status == StatusUnchanged) {
+					report.Entries[i].Status = StatusInvalid
+				}
+			}
+		}
+	}
+
+	unknown := map[string]bool{}
+	for i, values := range loaded {
+		if _, ok := sources[i].(*EnvSource); ok {
+			continue
+		}
+		for key := range values {
+			if _, ok := fileReferenced(target, key); ok {
+				continue
+			}
+			if _, ok := target[key]; !ok && !unknown[key] {
+				unknown[key] = true
+				report.Entries = append(report.Entries, DriftEntry{Name: key, Status: StatusUnknown})
+			}
+		}
+	}
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Name < report.Entries[j].Name
+	})
+	return report, nil
+}
+
+// ruleFailed reports if the named CrossRule has an error in the errors returned by validateRules.
+func ruleFailed(errs validation.Errors, rule CrossRule, ruleName string) bool {
+	ruleErrors, ok := errs[strings.Join(rule.Variables, ", ")].(validation.Errors)
+	if !ok {
+		return false
+	}
+	_, failed := ruleErrors[ruleName]
+	return failed
+}
+
+// isRequired reports if the Variable has a validation.Required rule.
+func isRequired(confVar *Variable) bool {
+	for _, rule := range confVar.Rules {
+		if _, ok := rule.(validation.RequiredRule); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Export formats of the configuration schema.
+const (
+	FormatEnvSample  = "env-sample"
+	FormatJSONSchema = "json-schema"
+	FormatMarkdown   = "markdown"
+	FormatConfigMap  = "configmap"
+	FormatHelmValues = "helm-values"
+)
+
+// ExportFormats are the formats accepted by Export and ExportFile.
+var ExportFormats = []string{FormatEnvSample, FormatJSONSchema, FormatMarkdown, FormatConfigMap, FormatHelmValues}
+
+// DefaultConfigMapName is the name of the ConfigMap rendered by Export.
+const DefaultConfigMapName = "app-config"
+
+// variableDoc is the documentation of a registered Variable.
+type variableDoc struct {
+	name         string
+	description  string
+	constraints  []string
+	defaultValue string
+	required     bool
+	sensitive    bool
+}
+
+// docs returns the documentation of the registered Variables in alphabetic order.
+// The default values are resolved for the environment like the getters, Sensitive default values are masked.
+func (appConf *AppConfig) docs() []variableDoc {
+	appConf.mu.RLock()
+	defer appConf.mu.RUnlock()
+	env := appConf.env()
+	docs := []variableDoc{}
+	for _, key := range appConf.keys() {
+		elem := appConf.vars[key]
+		docs = append(docs, variableDoc{
+			name:         key,
+			description:  elem.Description,
+			constraints:  appConf.constraints(key),
+			defaultValue: mask(elem.Sensitive, elem.DefaultFor(env)),
+			required:     isRequired(elem),
+			sensitive:    elem.Sensitive,
+		})
+	}
+	return docs
+}
+
+// JSONSchema renders the registered Variables as a JSON Schema (draft-07) of a flat object.
+// The rule names are listed in the non-standard x-constraints keyword, as they cannot be translated.
+// Sensitive Variables have no default, as the masked value is not a valid one.
+func (appConf *AppConfig) JSONSchema() ([]byte, error) {
+	type property struct {
+		Type        string   `json:"type"`
+		Description string   `json:"description,omitempty"`
+		Default     string   `json:"default,omitempty"`
+		Constraints []string `json:"x-constraints,omitempty"`
+		Sensitive   bool     `json:"x-sensitive,omitempty"`
+	}
+	schema := struct {
+		Schema     string              `json:"$schema"`
+		Type       string              `json:"type"`
+		Properties map[string]property `json:"properties"`
+		Required   []string            `json:"required,omitempty"`
+	}{
+		Schema:     "http://json-schema.org/draft-07/schema#",
+		Type:       "object",
+		Properties: map[string]property{},
+	}
+	for _, doc := range appConf.docs() {
+		prop := property{
+			Type:        "string",
+			Description: doc.description,
+			Constraints: doc.constraints,
+			Sensitive:   doc.sensitive,
+		}
+		if !doc.sensitive {
+			prop.Default = doc.defaultValue
+		}
+		schema.Properties[doc.name] = prop
+		if doc.required {
+			schema.Required = append(schema.Required, doc.name)
+		}
+	}
+
+	content, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to render JSON Schema")
+	}
+	return append(content, '\n'), nil
+}
+
+// escapeMarkdown escapes the characters which would break a Markdown table cell.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+// Markdown renders the registered Variables as a Markdown table.
+func (appConf *AppConfig) Markdown() string {
+	md := &strings.Builder{}
+	md.WriteString("| Variable Name | Description | Constraints | Default Value |\n")
+	md.WriteString("| --- | --- | --- | --- |\n")
+	for _, doc := range appConf.docs() {
+		defaultValue := ""
+		if doc.defaultValue != "" {
+			defaultValue = "`" + doc.defaultValue + "`"
+		}
+		fmt.Fprintf(md, "| `%s` | %s | %s | %s |\n",
+			doc.name,
+			escapeMarkdown(doc.description),
+			escapeMarkdown(strings.Join(doc.constraints, ", ")),
+			escapeMarkdown(defaultValue),
+		)
+	}
+	return md.String()
+}
+
+// yamlEntry creates the key and value nodes of a mapping entry, with the documentation as comment.
+func yamlEntry(doc variableDoc, value string) []*yaml.Node {
+	comment := doc.description
+	if len(doc.constraints) > 0 {
+		comment = strings.TrimSpace(fmt.Sprintf("%s (Constraints: %s)", comment, strings.Join(doc.constraints, ", ")))
+	}
+	return []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: doc.name, HeadComment: comment},
+		{Kind: yaml.ScalarNode, Value: value, Style: yaml.DoubleQuotedStyle},
+	}
+}
+
+// renderYAML encodes the document node.
+func renderYAML(doc *yaml.Node) ([]byte, error) {
+	out := &strings.Builder{}
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(out.String()), nil
+}
+
+// ConfigMap renders a Kubernetes ConfigMap with the given name, holding the default values of the
+// registered Variables. Sensitive Variables are left out, as they belong in a Secret.
+func (appConf *AppConfig) ConfigMap(name string) ([]byte, error) {
+	data := &yaml.Node{Kind: yaml.MappingNode}
+	for _, doc := range appConf.docs() {
+		if !doc.sensitive {
+			data.Content = append(data.Content, yamlEntry(doc, doc.defaultValue)...)
+		}
+	}
+	scalar := func(value string) *yaml.Node { return &yaml.Node{Kind: yaml.ScalarNode, Value: value} }
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		scalar("apiVersion"), scalar("v1"),
+		scalar("kind"), scalar("ConfigMap"),
+		scalar("metadata"), {Kind: yaml.MappingNode, Content: []*yaml.Node{scalar("name"), scalar(name)}},
+		scalar("data"), data,
+	}}
+
+	content, err := renderYAML(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to render ConfigMap")
+	}
+	return content, nil
+}
+
+// HelmValues renders a Helm values skeleton with the default values of the registered Variables under env,
+// and the Sensitive Variables with empty values under secretEnv.
+func (appConf *AppConfig) HelmValues() ([]byte, error) {
+	env := &yaml.Node{Kind: yaml.MappingNode}
+	secretEnv := &yaml.Node{Kind: yaml.MappingNode}
+	for _, doc := range appConf.docs() {
+		if doc.sensitive {
+			secretEnv.Content = append(secretEnv.Content, yamlEntry(doc, "")...)
+			continue
+		}
+		env.Content = append(env.Content, yamlEntry(doc, doc.defaultValue)...)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "env"}, env,
+		{Kind: yaml.ScalarNode, Value: "secretEnv"}, secretEnv,
+	}}
+
+	content, err := renderYAML(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to render Helm values")
+	}
+	return content, nil
+}
+
+// Export renders the registered Variables in the given format (one of ExportFormats).
+func (appConf *AppConfig) Export(format string) ([]byte, error) {
+	switch format {
+	case FormatEnvSample:
+		return []byte(appConf.sample()), nil
+	case FormatJSONSchema:
+		return appConf.JSONSchema()
+	case FormatMarkdown:
+		return []byte(appConf.Markdown()), nil
+	case FormatConfigMap:
+		return appConf.ConfigMap(DefaultConfigMapName)
+	case FormatHelmValues:
+		return appConf.HelmValues()
+	}
+	return nil, errors.Errorf("Unknown export format %s, use one of: %s", format, strings.Join(ExportFormats, ", "))
+}
+
+// ExportFile renders the registered Variables in the given format into the file, overwriting it if it exists.
+func (appConf *AppConfig) ExportFile(format, filename string) error {
+	content, err := appConf.Export(format)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename, content, 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write %s file", filename)
+	}
+	return nil
+}
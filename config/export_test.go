@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/yaml.v3"
+)
+
+func (cts *ConfigTestSuite) exportTestConfig() *AppConfig {
+	return NewConfig(map[string]*Variable{
+		"APP_PORT": {
+			DefaultValue: "8080",
+			Description:  "TCP/IP Port | listener",
+			Rules: map[string]validation.Rule{
+				"Required": validation.Required,
+				"Length":   validation.Length(1, 5),
+			},
+		},
+		"APP_DB_PASSWORD": {
+			DefaultValue: "secret",
+			Description:  "Password of the database",
+			Sensitive:    true,
+		},
+	})
+}
+
+func (cts *ConfigTestSuite) TestJSONSchema() {
+	content, err := cts.exportTestConfig().JSONSchema()
+	cts.NoError(err, "JSON Schema should have been rendered")
+
+	schema := map[string]interface{}{}
+	cts.NoError(json.Unmarshal(content, &schema), "JSON Schema should be valid JSON")
+	cts.Equal("object", schema["type"])
+	cts.Equal([]interface{}{"APP_PORT"}, schema["required"])
+	port := schema["properties"].(map[string]interface{})["APP_PORT"].(map[string]interface{})
+	cts.Equal("8080", port["default"])
+	cts.Equal([]interface{}{"Length", "Required"}, port["x-constraints"])
+	password := schema["properties"].(map[string]interface{})["APP_DB_PASSWORD"].(map[string]interface{})
+	cts.NotContains(password, "default", "Sensitive variables should have no default")
+	cts.Equal(true, password["x-sensitive"])
+
+	again, err := cts.exportTestConfig().JSONSchema()
+	cts.NoError(err)
+	cts.Equal(string(content), string(again), "The output should be deterministic")
+}
+
+func (cts *ConfigTestSuite) TestMarkdown() {
+	cts.Equal(
+		"| Variable Name | Description | Constraints | Default Value |\n"+
+			"| --- | --- | --- | --- |\n"+
+			"| `APP_DB_PASSWORD` | Password of the database |  | `******` |\n"+
+			"| `APP_PORT` | TCP/IP Port \\| listener | Length, Required | `8080` |\n",
+		cts.exportTestConfig().Markdown(),
+	)
+}
+
+func (cts *ConfigTestSuite) TestConfigMapAndHelmValues() {
+	content, err := cts.exportTestConfig().ConfigMap("my-service")
+	cts.NoError(err, "ConfigMap should have been rendered")
+	cts.Contains(string(content), "# TCP/IP Port | listener (Constraints: Length, Required)")
+	configMap := struct {
+		Kind     string
+		Metadata struct{ Name string }
+		Data     map[string]string
+	}{}
+	cts.NoError(yaml.Unmarshal(content, &configMap), "ConfigMap should be valid YAML")
+	cts.Equal("ConfigMap", configMap.Kind)
+	cts.Equal("my-service", configMap.Metadata.Name)
+	cts.Equal(map[string]string{"APP_PORT": "8080"}, configMap.Data, "Sensitive variables should be left out")
+
+	content, err = cts.exportTestConfig().HelmValues()
+	cts.NoError(err, "Helm values should have been rendered")
+	values := struct {
+		Env       map[string]string `yaml:"env"`
+		SecretEnv map[string]string `yaml:"secretEnv"`
+	}{}
+	cts.NoError(yaml.Unmarshal(content, &values), "Helm values should be valid YAML")
+	cts.Equal(map[string]string{"APP_PORT": "8080"}, values.Env)
+	cts.Equal(map[string]string{"APP_DB_PASSWORD": ""}, values.SecretEnv)
+}
+
+func (cts *ConfigTestSuite) TestExportEnvDefaults() {
+	conf := NewConfig(map[string]*Variable{
+		"APP_ENV": {DefaultValue: "production"},
+		"APP_PORT": {
+			DefaultValue: "8080",
+			EnvDefaults:  map[string]string{"production": "80"},
+		},
+	})
+	cts.Contains(conf.Markdown(), "| `APP_PORT` |  |  | `80` |", "The default of the environment should be exported")
+	content, err := conf.ConfigMap("my-service")
+	cts.NoError(err)
+	cts.Contains(string(content), `APP_PORT: "80"`)
+	content, err = conf.JSONSchema()
+	cts.NoError(err)
+	cts.Contains(string(content), `"default": "80"`)
+	cts.Contains(string(conf.sample()), "APP_PORT=80")
+}
+
+func (cts *ConfigTestSuite) TestExportFile() {
+	conf := cts.exportTestConfig()
+	for _, format := range ExportFormats {
+		file := cts.writeTempFile("config-export-*", "")
+		cts.NoErrorf(conf.ExportFile(format, file), "%s should have been exported", format)
+		content, err := ioutil.ReadFile(file)
+		cts.NoError(err)
+		cts.Contains(string(content), "APP_PORT")
+		cts.NotContains(string(content), "secret\n")
+		cts.NoError(os.Remove(file))
+	}
+	cts.EqualError(
+		conf.ExportFile("xml", "file.xml"),
+		"Unknown export format xml, use one of: env-sample, json-schema, markdown, configmap, helm-values",
+	)
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	// FilePrefix marks a value of an interpolated Variable as a file reference, like file:/run/secrets/db-password.
+	// The value is replaced with the content of the file (without the trailing newline).
+	FilePrefix = "file:"
+
+	// FileSuffix marks a variable as the file reference of the Variable without the suffix, like
+	// APP_DB_PASSWORD_FILE=/run/secrets/db-password for APP_DB_PASSWORD (the Docker and Kubernetes secret convention).
+	FileSuffix = "_FILE"
+)
+
+// RuleReference is the name of the validation error of the Variables which cannot be interpolated or read.
+const RuleReference = "Reference"
+
+// referencePattern matches the ${NAME} references and the $$ escapes of the values.
+var referencePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// fileReferenced returns the name of the Variable referenced by a name with the FileSuffix.
+func fileReferenced(vars map[string]*Variable, name string) (string, bool) {
+	if !strings.HasSuffix(name, FileSuffix) {
+		return "", false
+	}
+	if _, ok := vars[name]; ok {
+		return "", false
+	}
+	referenced := strings.TrimSuffix(name, FileSuffix)
+	_, ok := vars[referenced]
+	return referenced, ok
+}
+
+// resolver expands the ${NAME} references and reads the file references of the Variables.
+type resolver struct {
+	vars      map[string]*Variable
+	resolved  map[string]bool
+	resolving map[string]bool
+}
+
+// resolveValues expands the ${NAME} references to other Variables in the values (and defaults) of the
+// interpolated Variables, then replaces the file references with the content of the files. The failures are kept with the Variable,
+// and reported as validation errors by the AppConfig.
+func resolveValues(vars map[string]*Variable) {
+	r := &resolver{vars: vars, resolved: map[string]bool{}, resolving: map[string]bool{}}
+	keys := make([]string, 0, len(vars))
+	for key, confVar := range vars {
+		confVar.resolveErr = nil
+		confVar.sensitiveReference = false
+		keys = append(keys, key)
+	}
+	// Sort is needed for deterministic errors of the cyclic references
+	sort.Strings(keys)
+	for _, key := range keys {
+		// the error is kept with the Variable
+		_, _ = r.resolve(key)
+	}
+}
+
+// resolve returns the resolved value of the named Variable.
+func (r *resolver) resolve(key string) (string, error) {
+	confVar, ok := r.vars[key]
+	if !ok {
+		return "", validation.NewError("validation_reference_undefined", fmt.Sprintf("undefined reference to %s", key))
+	}
+	if r.resolved[key] {
+		return confVar.Value, confVar.resolveErr
+	}
+	if r.resolving[key] {
+		return "", validation.NewError("validation_reference_cycle", fmt.Sprintf("cyclic reference to %s", key))
+	}
+
+	r.resolving[key] = true
+	val := confVar.Value
+	var err error
+	if confVar.Interpolate {
+		val, confVar.sensitiveReference, err = r.expand(val)
+	}
+	if err == nil && (confVar.Interpolate || confVar.fileReference) && strings.HasPrefix(val, FilePrefix) {
+		val, err = readFileReference(strings.TrimPrefix(val, FilePrefix))
+	}
+	delete(r.resolving, key)
+	r.resolved[key] = true
+
+	if err != nil {
+		confVar.resolveErr = err
+		return "", err
+	}
+	confVar.Value = val
+	return val, nil
+}
+
+// expand replaces the ${NAME} references in the value with the resolved values of the Variables, and $$ with $.
+// It also reports if any of the referenced Variables is secret, as the expanded value contains the secret.
+func (r *resolver) expand(val string) (string, bool, error) {
+	var err error
+	sensitive := false
+	expanded := referencePattern.ReplaceAllStringFunc(val, func(match string) string {
+		if match == "$$" || err != nil {
+			return "$"
+		}
+		name := referencePattern.FindStringSubmatch(match)[1]
+		resolved, resolveErr := r.resolve(name)
+		if resolveErr != nil {
+			err = resolveErr
+		}
+		if referenced, ok := r.vars[name]; ok && referenced.secret() {
+			sensitive = true
+		}
+		return resolved
+	})
+	if err != nil {
+		return "", sensitive, err
+	}
+	return expanded, sensitive, nil
+}
+
+// readFileReference returns the content of the referenced file without the trailing newline.
+func readFileReference(filename string) (string, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", validation.NewError("validation_reference_file", fmt.Sprintf("cannot read file: %s", err))
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
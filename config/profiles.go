@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/toolbox/constants"
+)
+
+// DefaultFor returns the default value of the Variable in the given environment:
+// the EnvDefaults overlay of the environment if it is set, the DefaultValue otherwise.
+func (v *Variable) DefaultFor(env string) string {
+	if val, ok := v.EnvDefaults[env]; ok {
+		return val
+	}
+	return v.DefaultValue
+}
+
+// displayDefaults returns the (masked if Sensitive) DefaultValue followed by the EnvDefaults overlays,
+// like "8080; production=80".
+func (v *Variable) displayDefaults() string {
+	if len(v.EnvDefaults) == 0 {
+		return v.DisplayDefaultValue()
+	}
+	envs := make([]string, 0, len(v.EnvDefaults))
+	for env := range v.EnvDefaults {
+		envs = append(envs, env)
+	}
+	// Sort is needed because maps always return values in random order
+	sort.Strings(envs)
+	parts := []string{v.DisplayDefaultValue()}
+	for _, env := range envs {
+		parts = append(parts, fmt.Sprintf("%s=%s", env, mask(v.Sensitive, v.EnvDefaults[env])))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// isValidEnvironment reports if env is one of the constants.ValidEnvironments.
+func isValidEnvironment(env string) bool {
+	for _, valid := range constants.ValidEnvironments {
+		if env == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveEnv returns the environment (APP_ENV) of the loaded configuration:
+// the value of the last Source which set it, or the default of the APP_ENV Variable.
+func resolveEnv(vars map[string]*Variable, loaded []map[string]string) string {
+	env := ""
+	if envVar, ok := vars[constants.APP_ENV]; ok {
+		env = envVar.DefaultValue
+	}
+	for _, values := range loaded {
+		if val := values[constants.APP_ENV]; val != "" {
+			env = val
+		}
+	}
+	return env
+}
+
+// env returns the environment of the default values: the value of APP_ENV after the last load,
+// or its default before. The caller must hold the lock.
+func (appConf *AppConfig) env() string {
+	if envVar, ok := appConf.vars[constants.APP_ENV]; ok && envVar.Value != "" {
+		return envVar.Value
+	}
+	return resolveEnv(appConf.vars, nil)
+}
+
+// profileFiles returns the profile envfile of every envfile (.env -> .env.production) in the environment.
+func profileFiles(envfiles []string, env string) []string {
+	files := make([]string, 0, len(envfiles))
+	for _, file := range envfiles {
+		files = append(files, file+"."+env)
+	}
+	return files
+}
+
+// existingFiles returns the files which exist.
+func existingFiles(files []string) []string {
+	existing := []string{}
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			existing = append(existing, file)
+		}
+	}
+	return existing
+}
+
+// load loads the values of the configured Sources completed with the envfile(s), then the values of the
+// profile envfile(s) of the resolved environment. The profile of every EnvFileSource is loaded right after it.
+// Profiles are only loaded for the constants.ValidEnvironments.
+func (appConf *AppConfig) load(envfiles ...string) ([]Source, []map[string]string, error) {
+	sources, err := appConf.sourcesFor(envfiles...)
+	if err != nil {
+		return nil, nil, err
+	}
+	loaded, err := loadValues(sources)
+	if err != nil {
+		return nil, nil, err
+	}
+	appConf.mu.RLock()
+	legacy := len(appConf.sources) == 0 && !appConf.isolated
+	appConf.mu.RUnlock()
+	return appConf.withProfiles(sources, loaded, legacy)
+}
+
+// withProfiles inserts the profile envfile(s) of the resolved environment right after every EnvFileSource
+// of the loaded Sources. If overload is set, the profile envfile(s) overload the environment variables too.
+func (appConf *AppConfig) withProfiles(sources []Source, loaded []map[string]string, overload bool) ([]Source, []map[string]string, error) {
+	appConf.mu.RLock()
+	env := resolveEnv(appConf.vars, loaded)
+	appConf.mu.RUnlock()
+	if !isValidEnvironment(env) {
+		return sources, loaded, nil
+	}
+
+	withProfiles := make([]Source, 0, len(sources))
+	withProfilesLoaded := make([]map[string]string, 0, len(loaded))
+	for i, source := range sources {
+		withProfiles = append(withProfiles, source)
+		withProfilesLoaded = append(withProfilesLoaded, loaded[i])
+		envFileSource, ok := source.(*EnvFileSource)
+		if !ok {
+			continue
+		}
+		files := existingFiles(profileFiles(envFileSource.Files(), env))
+		if len(files) == 0 {
+			continue
+		}
+		if overload {
+			// Keep the behaviour of the envfile(s), which overload the environment variables without Sources
+			if err := appConf.overloadEnv(files...); err != nil {
+				return nil, nil, errors.Wrap(err, "Failed to overload variables with profile envfile(s)")
+			}
+		}
+		profile := NewEnvFileSource(files...)
+		values, err := profile.Load()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to load configuration from %s", profile.Name())
+		}
+		withProfiles = append(withProfiles, profile)
+		withProfilesLoaded = append(withProfilesLoaded, values)
+	}
+	return withProfiles, withProfilesLoaded, nil
+}
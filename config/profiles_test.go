@@ -0,0 +1,85 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/toolbox/constants"
+)
+
+func (cts *ConfigTestSuite) profileTestConfig() *AppConfig {
+	return NewConfig(map[string]*Variable{
+		constants.APP_ENV: {DefaultValue: constants.ENV_DEV},
+		"PROFILE_LEVEL": {
+			DefaultValue: "info",
+			EnvDefaults:  map[string]string{constants.ENV_PRODUCTION: "warn", constants.ENV_DEV: "debug"},
+		},
+		"PROFILE_PORT": {DefaultValue: "8080"},
+	})
+}
+
+func (cts *ConfigTestSuite) TestEnvDefaults() {
+	conf := cts.profileTestConfig().WithSources(NewEnvFileSource())
+	cts.NoError(conf.Setup(), "Configuration should have been set up")
+	cts.Equal("debug", conf.Get("PROFILE_LEVEL"), "The default of the dev environment should have been used")
+	cts.Equal(OriginDefault, conf.vars["PROFILE_LEVEL"].Origin)
+
+	envFile := cts.writeTempFile("config-profile-*.env", "APP_ENV=production\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+	conf = cts.profileTestConfig().WithSources(NewEnvFileSource(envFile))
+	cts.NoError(conf.Setup(), "Configuration should have been set up")
+	cts.Equal("warn", conf.Get("PROFILE_LEVEL"), "The default of the production environment should have been used")
+
+	cts.NoError(ioutil.WriteFile(envFile, []byte("APP_ENV=staging\n"), 0600))
+	cts.NoError(conf.Reload(), "Configuration should have been reloaded")
+	cts.Equal("info", conf.Get("PROFILE_LEVEL"), "The plain default should be used without overlay")
+
+	cts.Contains(conf.DumpTable(), "info; dev=debug; production=warn")
+}
+
+func (cts *ConfigTestSuite) TestProfileEnvfile() {
+	envFile := cts.writeTempFile("config-profile-*.env", "APP_ENV=production\nPROFILE_PORT=9090\nPROFILE_LEVEL=error\n")
+	profileFile := envFile + "." + constants.ENV_PRODUCTION
+	cts.NoError(ioutil.WriteFile(profileFile, []byte("PROFILE_PORT=80\n"), 0600))
+	defer func() {
+		for _, f := range []string{envFile, profileFile} {
+			cts.NoError(os.Remove(f), "Temp file should have been removed")
+		}
+	}()
+
+	conf := cts.profileTestConfig().WithSources(NewEnvFileSource(envFile))
+	cts.NoError(conf.Setup(), "Configuration should have been set up")
+	cts.Equal("80", conf.Get("PROFILE_PORT"), "The profile envfile should override the envfile")
+	cts.Equal("error", conf.Get("PROFILE_LEVEL"), "The envfile should override the environment default")
+	cts.Equal("envfile("+profileFile+")", conf.vars["PROFILE_PORT"].Origin)
+
+	// the profile envfile of a changed environment is picked up on reload
+	stagingFile := envFile + "." + constants.ENV_STAGING
+	cts.NoError(ioutil.WriteFile(stagingFile, []byte("PROFILE_PORT=8443\n"), 0600))
+	defer func() { cts.NoError(os.Remove(stagingFile), "Temp file should have been removed") }()
+	cts.NoError(ioutil.WriteFile(envFile, []byte("APP_ENV=staging\n"), 0600))
+	cts.True(conf.filesModified(), "The envfile change should have been detected")
+	cts.NoError(conf.Reload(), "Configuration should have been reloaded")
+	cts.Equal("8443", conf.Get("PROFILE_PORT"))
+}
+
+func (cts *ConfigTestSuite) TestProfileEnvfileLegacy() {
+	cts.setupEnvTest(constants.APP_ENV, "PROFILE_PORT")
+	defer func() {
+		cts.NoError(os.Unsetenv(constants.APP_ENV))
+		cts.NoError(os.Unsetenv("PROFILE_PORT"))
+	}()
+	envFile := cts.writeTempFile("config-profile-*.env", "APP_ENV=test\nPROFILE_PORT=9090\n")
+	profileFile := envFile + "." + constants.ENV_TEST
+	cts.NoError(ioutil.WriteFile(profileFile, []byte("PROFILE_PORT=7070\n"), 0600))
+	defer func() {
+		for _, f := range []string{envFile, profileFile} {
+			cts.NoError(os.Remove(f), "Temp file should have been removed")
+		}
+	}()
+
+	conf := cts.profileTestConfig()
+	cts.NoError(conf.Setup(envFile), "Configuration should have been set up")
+	cts.Equal("7070", conf.Get("PROFILE_PORT"), "The profile envfile should override the envfile")
+	cts.Equal("7070", os.Getenv("PROFILE_PORT"), "The profile envfile should overload the environment")
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/toolbox/constants"
+)
+
+// DefaultPollInterval is the default interval of checking the configuration files for modifications in Watch.
+const DefaultPollInterval = 5 * time.Second
+
+// Change describes the change of a Variable's value by a Reload.
+type Change struct {
+	// Name is the name of the changed Variable.
+	Name string
+
+	// OldValue is the value before the Reload.
+	OldValue string
+
+	// NewValue is the value after the Reload.
+	NewValue string
+
+	// Origin is the name of the Source the NewValue was loaded from.
+	Origin string
+}
+
+// Subscribe registers fn to be called when a Reload changes the value of the named Variable.
+// The callbacks are called synchronously by the goroutine which called Reload, after the new values
+// are in place. It returns a function which removes the subscription.
+func (appConf *AppConfig) Subscribe(name string, fn func(Change)) (unsubscribe func()) {
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	if appConf.subscribers == nil {
+		appConf.subscribers = map[string]map[int]func(Change){}
+	}
+	if appConf.subscribers[name] == nil {
+		appConf.subscribers[name] = map[int]func(Change){}
+	}
+	appConf.lastSubID++
+	id := appConf.lastSubID
+	appConf.subscribers[name][id] = fn
+
+	return func() {
+		appConf.mu.Lock()
+		defer appConf.mu.Unlock()
+		delete(appConf.subscribers[name], id)
+	}
+}
+
+// Reload reads the envfile(s) supplied to Setup and the configured Sources again, and validates the result.
+// The new values are only swapped in if they are all valid, otherwise the previous values are kept and
+// the filtered validation.Errors are returned. After a successful swap the subscribers of every changed
+// Variable are notified.
+func (appConf *AppConfig) Reload() error {
+	appConf.mu.RLock()
+	envfiles := appConf.envfiles
+	appConf.mu.RUnlock()
+
+	states := statFiles(appConf.watchedFiles(envfiles))
+	sources, loaded, err := appConf.load(envfiles...)
+	if err != nil {
+		return errors.Wrap(err, "Failed to reload Application Configuration")
+	}
+
+	appConf.mu.Lock()
+	// the files are only reloaded again if they are modified after this attempt, even if it fails
+	appConf.fileStates = states
+	next := make(map[string]*Variable, len(appConf.vars))
+	for key, confVar := range appConf.vars {
+		varCopy := *confVar
+		next[key] = &varCopy
+	}
+	applyValues(next, sources, loaded)
+	if errs := appConf.validate(next); len(errs) > 0 {
+		appConf.mu.Unlock()
+		return errs.Filter()
+	}
+
+	notify := []func(){}
+	for _, key := range appConf.keys() {
+		current := appConf.vars[key]
+		if current.Value != next[key].Value {
+			change := Change{Name: key, OldValue: current.Value, NewValue: next[key].Value, Origin: next[key].Origin}
+			for _, fn := range appConf.subscribers[key] {
+				fn := fn
+				notify = append(notify, func() { fn(change) })
+			}
+		}
+		current.Value = next[key].Value
+		current.Origin = next[key].Origin
+		current.resolveErr = next[key].resolveErr
+	}
+	appConf.mu.Unlock()
+
+	for _, fn := range notify {
+		fn()
+	}
+	return nil
+}
+
+// WatchOptions configures how Watch triggers a Reload.
+type WatchOptions struct {
+	// PollInterval is the interval of checking the envfile(s) and file based Sources for modifications.
+	// Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// ReloadInterval forces a Reload periodically, even if no file was modified, to pick up changes of
+	// remote Sources (like a rotated secret). Zero disables the periodic reload.
+	ReloadInterval time.Duration
+
+	// Signals trigger a Reload when received. Defaults to SIGHUP.
+	Signals []os.Signal
+
+	// OnError is called with the errors of the failed reloads (including invalid configurations).
+	OnError func(error)
+}
+
+// fileSource is a Source which reads files from the local filesystem.
+type fileSource interface {
+	Files() []string
+}
+
+// watchedFiles returns the envfile(s), the files of the file based Sources and the profile envfile(s)
+// of every valid environment, so the creation of a profile envfile is detected too.
+func (appConf *AppConfig) watchedFiles(envfiles []string) []string {
+	appConf.mu.RLock()
+	defer appConf.mu.RUnlock()
+	files := append([]string{}, envfiles...)
+	profiled := append([]string{}, envfiles...)
+	for _, source := range appConf.sources {
+		if fs, ok := source.(fileSource); ok {
+			files = append(files, fs.Files()...)
+		}
+		if efs, ok := source.(*EnvFileSource); ok {
+			profiled = append(profiled, efs.Files()...)
+		}
+	}
+	for _, env := range constants.ValidEnvironments {
+		files = append(files, profileFiles(profiled, env.(string))...)
+	}
+	return files
+}
+
+// fileState is the modification time and size of a file, or the error of retrieving them.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	err     string
+}
+
+// statFiles returns the current state of the files.
+func statFiles(files []string) map[string]fileState {
+	states := make(map[string]fileState, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			states[file] = fileState{err: err.Error()}
+			continue
+		}
+		states[file] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return states
+}
+
+// filesModified reports if any of the watched files changed since the last load.
+func (appConf *AppConfig) filesModified() bool {
+	appConf.mu.RLock()
+	envfiles, previous := appConf.envfiles, appConf.fileStates
+	appConf.mu.RUnlock()
+
+	current := statFiles(appConf.watchedFiles(envfiles))
+	if len(current) != len(previous) {
+		return true
+	}
+	for file, state := range current {
+		if previous[file] != state {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch reloads the AppConfig when a watched file is modified, a signal is received or the ReloadInterval
+// elapses, until the context is cancelled. Call it on its own goroutine after a successful Setup.
+func (appConf *AppConfig) Watch(ctx context.Context, opts WatchOptions) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.Signals == nil {
+		opts.Signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, opts.Signals...)
+	defer signal.Stop(signals)
+
+	poll := time.NewTicker(opts.PollInterval)
+	defer poll.Stop()
+
+	var forced <-chan time.Time
+	if opts.ReloadInterval > 0 {
+		ticker := time.NewTicker(opts.ReloadInterval)
+		defer ticker.Stop()
+		forced = ticker.C
+	}
+
+	reload := func() {
+		if err := appConf.Reload(); err != nil && opts.OnError != nil {
+			opts.OnError(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			reload()
+		case <-forced:
+			reload()
+		case <-poll.C:
+			if appConf.filesModified() {
+				reload()
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func (cts *ConfigTestSuite) reloadTestConfig() *AppConfig {
+	return NewConfig(map[string]*Variable{
+		"RELOAD_PORT": {
+			DefaultValue: "8080",
+			Rules:        map[string]validation.Rule{"Valid port": is.Port},
+		},
+		"RELOAD_LEVEL": {DefaultValue: "info"},
+	})
+}
+
+func (cts *ConfigTestSuite) TestReload() {
+	envFile := cts.writeTempFile("config-reload-*.env", "RELOAD_PORT=9090\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+
+	conf := cts.reloadTestConfig().WithSources(NewEnvFileSource(envFile))
+	cts.NoError(conf.Setup(), "Configuration should have been set up")
+	cts.Equal("9090", conf.Get("RELOAD_PORT"))
+
+	changes := []Change{}
+	unsubscribe := conf.Subscribe("RELOAD_LEVEL", func(change Change) {
+		changes = append(changes, change)
+	})
+	conf.Subscribe("RELOAD_PORT", func(change Change) {
+		changes = append(changes, change)
+	})
+
+	cts.NoError(ioutil.WriteFile(envFile, []byte("RELOAD_PORT=9090\nRELOAD_LEVEL=debug\n"), 0600))
+	cts.NoError(conf.Reload(), "Configuration should have been reloaded")
+	cts.Equal("debug", conf.Get("RELOAD_LEVEL"))
+	cts.Equal([]Change{{Name: "RELOAD_LEVEL", OldValue: "info", NewValue: "debug", Origin: "envfile(" + envFile + ")"}}, changes,
+		"Only the subscribers of the changed variable should have been notified")
+
+	// invalid values are not swapped in
+	cts.NoError(ioutil.WriteFile(envFile, []byte("RELOAD_PORT=not-a-port\nRELOAD_LEVEL=warn\n"), 0600))
+	err := conf.Reload()
+	cts.Error(err, "Reload should fail on invalid configuration")
+	cts.IsType(validation.Errors{}, err)
+	cts.Equal("9090", conf.Get("RELOAD_PORT"), "The previous value should have been kept")
+	cts.Equal("debug", conf.Get("RELOAD_LEVEL"), "The previous value should have been kept")
+	cts.Len(changes, 1, "Subscribers should not be notified on failed reload")
+
+	unsubscribe()
+	cts.NoError(ioutil.WriteFile(envFile, []byte("RELOAD_PORT=7070\nRELOAD_LEVEL=error\n"), 0600))
+	cts.NoError(conf.Reload(), "Configuration should have been reloaded")
+	cts.Len(changes, 2, "Only the remaining subscriber should have been notified")
+	cts.Equal("RELOAD_PORT", changes[1].Name)
+}
+
+func (cts *ConfigTestSuite) TestWatch() {
+	envFile := cts.writeTempFile("config-watch-*.env", "RELOAD_LEVEL=info\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+
+	conf := cts.reloadTestConfig().WithSources(NewEnvFileSource(envFile))
+	cts.NoError(conf.Setup(), "Configuration should have been set up")
+
+	changed := make(chan Change, 1)
+	conf.Subscribe("RELOAD_LEVEL", func(change Change) { changed <- change })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conf.Watch(ctx, WatchOptions{PollInterval: 10 * time.Millisecond})
+	}()
+
+	// concurrent reads during the reloads
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				_ = conf.Get("RELOAD_LEVEL")
+				_ = conf.DumpTable()
+			}
+		}()
+	}
+
+	cts.NoError(ioutil.WriteFile(envFile, []byte("RELOAD_LEVEL=warning\n"), 0600))
+	select {
+	case change := <-changed:
+		cts.Equal("warning", change.NewValue)
+	case <-time.After(2 * time.Second):
+		cts.Fail("The modification of the envfile should have triggered a reload")
+	}
+	cancel()
+	wg.Wait()
+	cts.Equal("warning", conf.Get("RELOAD_LEVEL"))
+}
+
+func (cts *ConfigTestSuite) TestReloadResetsReferenceErrors() {
+	envFile := cts.writeTempFile("config-reload-*.env", "RELOAD_LEVEL='${RELOAD_UNDEFINED}'\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+
+	conf := cts.reloadTestConfig().WithSources(NewEnvFileSource(envFile))
+	conf.vars["RELOAD_LEVEL"].Interpolate = true
+	cts.Error(conf.Setup(), "Undefined references should fail the setup")
+
+	cts.NoError(ioutil.WriteFile(envFile, []byte("RELOAD_LEVEL=debug\n"), 0600))
+	cts.NoError(conf.Reload(), "Configuration should have been reloaded")
+	cts.Equal("debug", conf.Get("RELOAD_LEVEL"))
+	cts.NoError(conf.Validate(), "The reference error of the previous load should have been reset")
+}
+
+func (cts *ConfigTestSuite) TestReloadRemovedVariables() {
+	envFile := cts.writeTempFile("config-reload-*.env", "RELOAD_PORT=9090\nRELOAD_LEVEL=debug\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+	cts.NoError(os.Setenv("RELOAD_PORT", "7070"))
+	defer func() {
+		cts.NoError(os.Unsetenv("RELOAD_PORT"))
+		cts.NoError(os.Unsetenv("RELOAD_LEVEL"))
+	}()
+
+	// Without Sources the envfile overloads the environment
+	conf := cts.reloadTestConfig()
+	cts.NoError(conf.Setup(envFile), "Configuration should have been set up")
+	cts.Equal("9090", conf.Get("RELOAD_PORT"))
+	cts.Equal("debug", conf.Get("RELOAD_LEVEL"))
+
+	cts.NoError(ioutil.WriteFile(envFile, []byte("\n"), 0600))
+	cts.NoError(conf.Reload(), "Configuration should have been reloaded")
+	cts.Equal("info", conf.Get("RELOAD_LEVEL"), "The removed variable should have its default value")
+	cts.Equal("7070", conf.Get("RELOAD_PORT"), "The original environment variable should have been restored")
+	_, set := os.LookupEnv("RELOAD_LEVEL")
+	cts.False(set, "The removed variable should have been unset")
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// CrossRule is a configuration level validation rule, which checks the values of multiple Variables together,
+// like "APP_DB_SECRET_NAME is required in production" or "TLS_CERT and TLS_KEY are set together".
+type CrossRule struct {
+	// Variables are the names of the Variables the rule depends on.
+	Variables []string
+
+	// Validate is called with the values of the Variables (unregistered Variables have empty values).
+	Validate func(values map[string]string) error
+}
+
+// WithRules adds the named CrossRules to the AppConfig. They are evaluated after the rules of the Variables,
+// and their errors are keyed by the comma separated names of their Variables in the validation.Errors.
+func (appConf *AppConfig) WithRules(rules map[string]CrossRule) *AppConfig {
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	if appConf.rules == nil {
+		appConf.rules = make(map[string]CrossRule, len(rules))
+	}
+	for name, rule := range rules {
+		appConf.rules[name] = rule
+	}
+	return appConf
+}
+
+// RequiredIf creates a CrossRule, which requires the named Variable when the condition Variable
+// has any of the supplied values, like RequiredIf(APP_DB_SECRET_NAME, APP_ENV, "production").
+func RequiredIf(name, condName string, condValues ...string) CrossRule {
+	return CrossRule{
+		Variables: []string{name, condName},
+		Validate: func(values map[string]string) error {
+			if values[name] != "" {
+				return nil
+			}
+			for _, condValue := range condValues {
+				if values[condName] == condValue {
+					return validation.NewError(
+						"validation_required_if",
+						fmt.Sprintf("%s cannot be blank when %s is %s", name, condName, condValue),
+					)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// AllOrNone creates a CrossRule, which requires that either all or none of the named Variables are set.
+func AllOrNone(names ...string) CrossRule {
+	return CrossRule{
+		Variables: names,
+		Validate: func(values map[string]string) error {
+			set := 0
+			for _, name := range names {
+				if values[name] != "" {
+					set++
+				}
+			}
+			if set != 0 && set != len(names) {
+				return validation.NewError(
+					"validation_all_or_none",
+					fmt.Sprintf("%s must be set all together or none of them", strings.Join(names, ", ")),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// validateRules applies the CrossRules on the Variables, and returns the errors keyed by the names of
+// the Variables of the failed rules.
+func validateRules(vars map[string]*Variable, rules map[string]CrossRule) validation.Errors {
+	allErrors := validation.Errors{}
+	for ruleName, rule := range rules {
+		values := make(map[string]string, len(rule.Variables))
+		for _, name := range rule.Variables {
+			if confVar, ok := vars[name]; ok {
+				values[name] = confVar.Value
+			}
+		}
+		if err := rule.Validate(values); err != nil {
+			key := strings.Join(rule.Variables, ", ")
+			ruleErrors, ok := allErrors[key].(validation.Errors)
+			if !ok {
+				ruleErrors = validation.Errors{}
+			}
+			ruleErrors[ruleName] = err
+			allErrors[key] = ruleErrors
+		}
+	}
+
+	if len(allErrors) > 0 {
+		return allErrors
+	}
+
+	return nil
+}
+
+// validate applies the rules of the Variables then the CrossRules, and unifies the errors.
+// The caller must hold the lock of the AppConfig.
+func (appConf *AppConfig) validate(vars map[string]*Variable) validation.Errors {
+	allErrors := validateVars(vars)
+	for key, err := range validateRules(vars, appConf.rules) {
+		if allErrors == nil {
+			allErrors = validation.Errors{}
+		}
+		allErrors[key] = err
+	}
+	return allErrors
+}
+
+// constraints returns the sorted names of the rules of the Variable and the CrossRules depending on it.
+// The caller must hold the lock of the AppConfig.
+func (appConf *AppConfig) constraints(key string) []string {
+	constraints := []string{}
+	for ruleName := range appConf.vars[key].Rules {
+		constraints = append(constraints, ruleName)
+	}
+	for ruleName, rule := range appConf.rules {
+		for _, name := range rule.Variables {
+			if name == key {
+				constraints = append(constraints, ruleName)
+				break
+			}
+		}
+	}
+	// Sort is needed because maps always return values in random order
+	sort.Strings(constraints)
+	return constraints
+}
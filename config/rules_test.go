@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/toolbox/constants"
+)
+
+func (cts *ConfigTestSuite) rulesTestConfig() *AppConfig {
+	return NewConfig(map[string]*Variable{
+		constants.APP_ENV:   {DefaultValue: constants.ENV_DEV},
+		"RULES_SECRET_NAME": {Description: "Name of the DB secret"},
+		"RULES_TLS_CERT":    {},
+		"RULES_TLS_KEY":     {Sensitive: true},
+	}).WithRules(map[string]CrossRule{
+		"Required in production": RequiredIf("RULES_SECRET_NAME", constants.APP_ENV, constants.ENV_PRODUCTION),
+		"TLS pair":               AllOrNone("RULES_TLS_CERT", "RULES_TLS_KEY"),
+	})
+}
+
+func (cts *ConfigTestSuite) TestCrossRules() {
+	conf := cts.rulesTestConfig().WithSources(NewEnvFileSource())
+	cts.NoError(conf.Setup(), "Default configuration should be valid")
+
+	envFile := cts.writeTempFile("config-rules-*.env", "APP_ENV=production\nRULES_TLS_KEY=secret-key\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+	conf = cts.rulesTestConfig().WithSources(NewEnvFileSource(envFile))
+	err := conf.Setup()
+	cts.Error(err, "Configuration should be invalid")
+	cts.IsType(validation.Errors{}, err)
+	errs := err.(validation.Errors)
+	cts.Len(errs, 2)
+	cts.EqualError(errs["RULES_SECRET_NAME, APP_ENV"],
+		"Required in production: RULES_SECRET_NAME cannot be blank when APP_ENV is production.")
+	cts.EqualError(errs["RULES_TLS_CERT, RULES_TLS_KEY"],
+		"TLS pair: RULES_TLS_CERT, RULES_TLS_KEY must be set all together or none of them.")
+	cts.NotContains(err.Error(), "secret-key", "Sensitive values should not be in the errors")
+
+	cts.Contains(conf.DumpTable(), "Required in production")
+	cts.Contains(conf.sample(), "# Description:  # Constraints: TLS pair\nRULES_TLS_CERT=")
+
+	report, err := cts.rulesTestConfig().Drift(NewEnvFileSource(envFile))
+	cts.NoError(err, "Drift report should have been created")
+	cts.Equal(4, report.Failed(), "The variables of the failed rules should be invalid")
+	cts.Len(report.Errors, 2)
+}
@@ -0,0 +1,395 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// OriginDefault is the origin of the Variables which were not set by any Source.
+const OriginDefault = "default"
+
+// Source provides configuration values from a single origin (environment, file, remote store, etc.).
+// Sources are composed with AppConfig.WithSources, where later Sources take precedence over earlier ones.
+type Source interface {
+	// Name identifies the Source, it is shown as the origin of the values in DumpTable.
+	Name() string
+
+	// Load returns the configuration values known by the Source keyed by variable name.
+	// Empty values are treated as unset, and unregistered names are ignored by the AppConfig.
+	Load() (map[string]string, error)
+}
+
+// WithSources sets the ordered list of Sources the AppConfig is loaded from by Setup.
+// The defaults have the lowest precedence, then every Source overrides the previous ones.
+// Without Sources Setup loads the environment, then overloads it with the supplied envfile(s).
+// The envfile(s) supplied to Setup are loaded after the Sources, so they override them (flags and secrets too).
+// To place the envfile(s) elsewhere in the order, add a NewEnvFileSource to the Sources and call Setup without envfiles.
+func (appConf *AppConfig) WithSources(sources ...Source) *AppConfig {
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	appConf.sources = append(appConf.sources, sources...)
+	return appConf
+}
+
+// Isolated makes the AppConfig load the envfile(s) (and the profile envfiles) without Sources the same way,
+// but without overloading the process environment with them. The environment is still read.
+func (appConf *AppConfig) Isolated() *AppConfig {
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	appConf.isolated = true
+	return appConf
+}
+
+// loadValues loads the values of every Source in order.
+func loadValues(sources []Source) ([]map[string]string, error) {
+	loaded := make([]map[string]string, len(sources))
+	for i, source := range sources {
+		values, err := source.Load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to load configuration from %s", source.Name())
+		}
+		loaded[i] = values
+	}
+	return loaded, nil
+}
+
+// applyValues resets every Variable to its default in the resolved environment,
+// then applies the loaded values of the Sources in order. A variable with the FileSuffix sets the
+// referenced Variable to a file reference, unless the Source sets the Variable too.
+// Finally the references in the values of the interpolated Variables and the file references are resolved.
+func applyValues(vars map[string]*Variable, sources []Source, loaded []map[string]string) {
+	env := resolveEnv(vars, loaded)
+	for _, confVar := range vars {
+		confVar.Value = confVar.DefaultFor(env)
+		confVar.Origin = OriginDefault
+		confVar.fileReference = false
+	}
+	for i, values := range loaded {
+		for key, val := range values {
+			if name, ok := fileReferenced(vars, key); ok && val != "" && values[name] == "" {
+				vars[name].Value = FilePrefix + val
+				vars[name].Origin = sources[i].Name()
+				vars[name].fileReference = true
+			}
+		}
+		for confKey, confVar := range vars {
+			if val := values[confKey]; val != "" {
+				confVar.Value = val
+				confVar.Origin = sources[i].Name()
+				confVar.fileReference = false
+			}
+		}
+	}
+	resolveValues(vars)
+}
+
+/////////////////////
+// Built-in Sources //
+/////////////////////
+
+// EnvSource loads the configuration from the environment variables of the process.
+type EnvSource struct{}
+
+// NewEnvSource creates a Source which reads the process environment.
+func NewEnvSource() *EnvSource {
+	return &EnvSource{}
+}
+
+// Name returns "environment".
+func (*EnvSource) Name() string {
+	return "environment"
+}
+
+// Load returns all the environment variables of the process.
+func (*EnvSource) Load() (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range os.Environ() {
+		if i := strings.Index(pair, "="); i > 0 {
+			values[pair[:i]] = pair[i+1:]
+		}
+	}
+	return values, nil
+}
+
+// MapSource provides the configuration from an in-memory map, like the values of a test.
+type MapSource struct {
+	values map[string]string
+}
+
+// NewMapSource creates a Source which returns a copy of the supplied values.
+func NewMapSource(values map[string]string) *MapSource {
+	return &MapSource{values: values}
+}
+
+// Name returns "map".
+func (*MapSource) Name() string {
+	return "map"
+}
+
+// Load returns a copy of the values.
+func (ms *MapSource) Load() (map[string]string, error) {
+	values := make(map[string]string, len(ms.values))
+	for key, val := range ms.values {
+		values[key] = val
+	}
+	return values, nil
+}
+
+// EnvFileSource loads the configuration from envfile(s), without modifying the process environment.
+// If a variable is set in more than one file, the last one wins.
+type EnvFileSource struct {
+	files []string
+}
+
+// NewEnvFileSource creates a Source which reads the supplied envfile(s).
+func NewEnvFileSource(files ...string) *EnvFileSource {
+	return &EnvFileSource{files: files}
+}
+
+// Name returns "envfile(<files>)".
+func (efs *EnvFileSource) Name() string {
+	return fmt.Sprintf("envfile(%s)", strings.Join(efs.files, ", "))
+}
+
+// Files returns the envfile(s) read by the Source.
+func (efs *EnvFileSource) Files() []string {
+	return efs.files
+}
+
+// Load parses the envfile(s) and returns their variables.
+func (efs *EnvFileSource) Load() (map[string]string, error) {
+	values := map[string]string{}
+	for _, file := range efs.files {
+		fileValues, err := godotenv.Read(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read envfile %s", file)
+		}
+		for key, val := range fileValues {
+			values[key] = val
+		}
+	}
+	return values, nil
+}
+
+// JSONFileSource loads the configuration from a JSON file containing a flat object,
+// like {"APP_PORT": 8080, "APP_DEBUG": true, "APP_HOSTS": ["a", "b"]}.
+type JSONFileSource struct {
+	file string
+}
+
+// NewJSONFileSource creates a Source which reads the supplied JSON file.
+func NewJSONFileSource(file string) *JSONFileSource {
+	return &JSONFileSource{file: file}
+}
+
+// Name returns "json(<file>)".
+func (jfs *JSONFileSource) Name() string {
+	return fmt.Sprintf("json(%s)", jfs.file)
+}
+
+// Files returns the JSON file read by the Source.
+func (jfs *JSONFileSource) Files() []string {
+	return []string{jfs.file}
+}
+
+// Load parses the JSON file and returns its values.
+func (jfs *JSONFileSource) Load() (map[string]string, error) {
+	content, err := ioutil.ReadFile(jfs.file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read JSON file %s", jfs.file)
+	}
+	values, err := parseJSONObject(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse JSON file %s", jfs.file)
+	}
+	return values, nil
+}
+
+// YAMLFileSource loads the configuration from a YAML file containing a flat mapping,
+// like "APP_PORT: 8080".
+type YAMLFileSource struct {
+	file string
+}
+
+// NewYAMLFileSource creates a Source which reads the supplied YAML file.
+func NewYAMLFileSource(file string) *YAMLFileSource {
+	return &YAMLFileSource{file: file}
+}
+
+// Name returns "yaml(<file>)".
+func (yfs *YAMLFileSource) Name() string {
+	return fmt.Sprintf("yaml(%s)", yfs.file)
+}
+
+// Files returns the YAML file read by the Source.
+func (yfs *YAMLFileSource) Files() []string {
+	return []string{yfs.file}
+}
+
+// Load parses the YAML file and returns its values.
+func (yfs *YAMLFileSource) Load() (map[string]string, error) {
+	content, err := ioutil.ReadFile(yfs.file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read YAML file %s", yfs.file)
+	}
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse YAML file %s", yfs.file)
+	}
+	values, err := stringifyValues(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse YAML file %s", yfs.file)
+	}
+	return values, nil
+}
+
+// FlagSource loads the configuration from the flags of a parsed flag.FlagSet.
+// Only the flags which were explicitly set on the command line are returned.
+// Flag names are converted to variable names by upper-casing them and replacing dashes with underscores,
+// so both -APP_PORT and -app-port set APP_PORT.
+type FlagSource struct {
+	flags *flag.FlagSet
+}
+
+// NewFlagSource creates a Source which reads the supplied FlagSet.
+// Use AppConfig.RegisterFlags to define a flag for every registered Variable.
+func NewFlagSource(flags *flag.FlagSet) *FlagSource {
+	return &FlagSource{flags: flags}
+}
+
+// Name returns "flags".
+func (*FlagSource) Name() string {
+	return "flags"
+}
+
+// Load returns the values of the flags which were set.
+func (fs *FlagSource) Load() (map[string]string, error) {
+	if !fs.flags.Parsed() {
+		return nil, errors.Errorf("Flags of %s are not parsed", fs.flags.Name())
+	}
+	values := map[string]string{}
+	fs.flags.Visit(func(f *flag.Flag) {
+		values[FlagToVariableName(f.Name)] = f.Value.String()
+	})
+	return values, nil
+}
+
+// FlagToVariableName converts a flag name (app-port) to a configuration variable name (APP_PORT).
+func FlagToVariableName(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// VariableToFlagName converts a configuration variable name (APP_PORT) to a flag name (app-port).
+func VariableToFlagName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// RegisterFlags defines a string flag on the FlagSet for every registered Variable,
+// using the Variable's Description as usage and DefaultValue (masked if Sensitive) as the displayed default.
+func (appConf *AppConfig) RegisterFlags(flags *flag.FlagSet) {
+	appConf.mu.RLock()
+	defer appConf.mu.RUnlock()
+	for _, key := range appConf.keys() {
+		confVar := appConf.vars[key]
+		flags.String(VariableToFlagName(key), confVar.DisplayDefaultValue(), confVar.Description)
+	}
+}
+
+// SecretGetter can look up a secret by name and return it as a string, like aws.SecretsManager.
+type SecretGetter interface {
+	GetSecret(secretName string) (string, error)
+}
+
+// SecretsManagerSource loads the configuration from a secret holding a flat JSON object,
+// like the ones stored in AWS SecretsManager.
+type SecretsManagerSource struct {
+	getter     SecretGetter
+	secretName string
+}
+
+// NewSecretsManagerSource creates a Source which looks up the named secret with the supplied SecretGetter
+// (usually an aws.SecretsManager).
+func NewSecretsManagerSource(getter SecretGetter, secretName string) *SecretsManagerSource {
+	return &SecretsManagerSource{getter: getter, secretName: secretName}
+}
+
+// Name returns "secretsmanager(<secretName>)".
+func (sms *SecretsManagerSource) Name() string {
+	return fmt.Sprintf("secretsmanager(%s)", sms.secretName)
+}
+
+// Load retrieves the secret and returns its values.
+func (sms *SecretsManagerSource) Load() (map[string]string, error) {
+	secret, err := sms.getter.GetSecret(sms.secretName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get secret %s", sms.secretName)
+	}
+	values, err := parseJSONObject([]byte(secret))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse secret %s", sms.secretName)
+	}
+	return values, nil
+}
+
+// parseJSONObject decodes a flat JSON object into string values, keeping the exact representation of numbers.
+func parseJSONObject(content []byte) (map[string]string, error) {
+	raw := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return stringifyValues(raw)
+}
+
+// stringifyValues converts the scalar (and list of scalar) values of a decoded document into strings.
+// Lists are joined with ListSeparator, nested objects are not supported.
+func stringifyValues(raw map[string]interface{}) (map[string]string, error) {
+	values := make(map[string]string, len(raw))
+	for key, val := range raw {
+		str, err := stringifyValue(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid value of %s", key)
+		}
+		values[key] = str
+	}
+	return values, nil
+}
+
+func stringifyValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int, int64, uint64:
+		return fmt.Sprint(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			str, err := stringifyValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, str)
+		}
+		return strings.Join(items, ListSeparator), nil
+	}
+	return "", errors.Errorf("unsupported value type %T", val)
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// testSecretGetter is a SecretGetter returning a fixed secret.
+type testSecretGetter struct {
+	secret string
+	err    error
+}
+
+func (tsg *testSecretGetter) GetSecret(secretName string) (string, error) {
+	return tsg.secret, tsg.err
+}
+
+// writeTempFile creates a temporary file with the supplied content and returns its name.
+func (cts *ConfigTestSuite) writeTempFile(pattern, content string) string {
+	tmpfile, err := ioutil.TempFile(os.TempDir(), pattern)
+	cts.NoError(err, "Temp file should have been created")
+	_, err = tmpfile.WriteString(content)
+	cts.NoError(err, "Content should have been written to the temp file")
+	cts.NoError(tmpfile.Close(), "Temp file should have been closed")
+	return tmpfile.Name()
+}
+
+func (cts *ConfigTestSuite) sourceTestConfig() *AppConfig {
+	return NewConfig(map[string]*Variable{
+		"SRC_A": {DefaultValue: "default-a"},
+		"SRC_B": {DefaultValue: "default-b"},
+		"SRC_C": {DefaultValue: "default-c"},
+		"SRC_D": {DefaultValue: "default-d"},
+		"SRC_E": {DefaultValue: "default-e"},
+	})
+}
+
+func (cts *ConfigTestSuite) TestSourcesPrecedence() {
+	envFile := cts.writeTempFile("config-source-*.env", "SRC_A=envfile-a\nSRC_B=envfile-b\n")
+	jsonFile := cts.writeTempFile("config-source-*.json", `{"SRC_B": "json-b", "SRC_C": 3, "SRC_X": {"nested": true}}`)
+	yamlFile := cts.writeTempFile("config-source-*.yaml", "SRC_C: yaml-c\nSRC_D:\n  - one\n  - two\n")
+	defer func() {
+		for _, f := range []string{envFile, jsonFile, yamlFile} {
+			cts.NoError(os.Remove(f), "Temp file should have been removed")
+		}
+	}()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	conf := cts.sourceTestConfig()
+	conf.RegisterFlags(flags)
+	cts.NoError(flags.Parse([]string{"-src-e", "flag-e"}), "Flags should have been parsed")
+
+	// the nested object in the JSON file is not supported
+	conf.WithSources(NewEnvFileSource(envFile), NewJSONFileSource(jsonFile))
+	err := conf.Setup()
+	cts.Error(err, "Nested JSON object should not be supported")
+	cts.Contains(err.Error(), "Invalid value of SRC_X")
+
+	jsonFile2 := cts.writeTempFile("config-source-*.json", `{"SRC_B": "json-b", "SRC_C": 3}`)
+	defer func() { cts.NoError(os.Remove(jsonFile2), "Temp file should have been removed") }()
+
+	conf = cts.sourceTestConfig().WithSources(
+		NewEnvFileSource(envFile),
+		NewJSONFileSource(jsonFile2),
+		NewYAMLFileSource(yamlFile),
+		NewFlagSource(flags),
+	)
+	cts.NoError(conf.Setup(), "Configuration should have been set up from the sources")
+	cts.Equal("envfile-a", conf.Get("SRC_A"))
+	cts.Equal("json-b", conf.Get("SRC_B"))
+	cts.Equal("yaml-c", conf.Get("SRC_C"))
+	cts.Equal("one,two", conf.Get("SRC_D"))
+	cts.Equal("flag-e", conf.Get("SRC_E"))
+
+	tab := conf.DumpTable()
+	for _, origin := range []string{"envfile(" + envFile + ")", "json(" + jsonFile2 + ")", "yaml(" + yamlFile + ")", "flags"} {
+		cts.Containsf(tab, origin, "The table should show the %s source", origin)
+	}
+}
+
+func (cts *ConfigTestSuite) TestSecretsManagerSource() {
+	getter := &testSecretGetter{secret: `{"SRC_A": "secret-a", "SRC_B": 42}`}
+	conf := cts.sourceTestConfig().WithSources(NewSecretsManagerSource(getter, "my-secret"))
+	cts.NoError(conf.Setup(), "Configuration should have been set up from the secret")
+	cts.Equal("secret-a", conf.Get("SRC_A"))
+	cts.Equal("42", conf.Get("SRC_B"))
+	cts.Equal("default-c", conf.Get("SRC_C"))
+	cts.Equal("secretsmanager(my-secret)", conf.vars["SRC_A"].Origin)
+	cts.Equal(OriginDefault, conf.vars["SRC_C"].Origin)
+
+	getter.err = errors.New("access denied")
+	cts.EqualError(
+		conf.Setup(),
+		"Failed to set Application Configuration: Failed to load configuration from secretsmanager(my-secret): Failed to get secret my-secret: access denied",
+	)
+}
+
+func (cts *ConfigTestSuite) TestEnvfilePrecedence() {
+	envFile := cts.writeTempFile("config-source-*.env", "SRC_A=envfile-a\nSRC_B=envfile-b\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+	getter := &testSecretGetter{secret: `{"SRC_A": "secret-a"}`}
+
+	conf := cts.sourceTestConfig().WithSources(NewSecretsManagerSource(getter, "my-secret"))
+	cts.NoError(conf.Setup(envFile), "Configuration should have been set up")
+	cts.Equal("envfile-a", conf.Get("SRC_A"), "The envfile of Setup should override the sources")
+
+	conf = cts.sourceTestConfig().WithSources(NewEnvFileSource(envFile), NewSecretsManagerSource(getter, "my-secret"))
+	cts.NoError(conf.Setup(), "Configuration should have been set up")
+	cts.Equal("secret-a", conf.Get("SRC_A"), "The sources should override the envfile placed before them")
+	cts.Equal("envfile-b", conf.Get("SRC_B"))
+}
+
+func (cts *ConfigTestSuite) TestEnvSourceWithEnvfile() {
+	envFile := cts.writeTempFile("config-source-*.env", "SRC_B=envfile-b\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+	cts.setEnvVars(map[string]string{"SRC_A": "env-a", "SRC_B": "env-b"})
+	defer func() {
+		cts.NoError(os.Unsetenv("SRC_A"))
+		cts.NoError(os.Unsetenv("SRC_B"))
+	}()
+
+	conf := cts.sourceTestConfig().WithSources(NewEnvSource())
+	cts.NoError(conf.Setup(envFile), "Configuration should have been set up")
+	cts.Equal("env-a", conf.Get("SRC_A"))
+	cts.Equal("envfile-b", conf.Get("SRC_B"), "The envfile should be loaded after the sources")
+	cts.Equal("env-b", os.Getenv("SRC_B"), "EnvFileSource should not modify the environment")
+	cts.Equal("environment", conf.vars["SRC_A"].Origin)
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// NewTestConfig creates an Isolated AppConfig for tests with copies of the supplied Variables, loaded from
+// the supplied values only. The process environment is neither read nor written, so the tests can run in parallel.
+// The envfile(s) passed to Setup or Reload are loaded after the values, still without touching the environment.
+func NewTestConfig(t *testing.T, defaults map[string]*Variable, values map[string]string) *AppConfig {
+	assert := require.New(t)
+	vars := make(map[string]*Variable, len(defaults))
+	for key, confVar := range defaults {
+		varCopy := *confVar
+		vars[key] = &varCopy
+	}
+
+	conf := NewConfig(vars).WithSources(NewMapSource(values)).Isolated()
+	assert.NoError(conf.loadEnv(), "Test configuration should have been loaded")
+	return conf
+}
+
+// SetForTest sets the value of the registered Variable until the end of the test,
+// when the previous value and origin are restored.
+func (appConf *AppConfig) SetForTest(t *testing.T, name, value string) {
+	assert := require.New(t)
+	appConf.mu.Lock()
+	defer appConf.mu.Unlock()
+	confVar, ok := appConf.vars[name]
+	assert.True(ok, "Configuration variable %s should be registered", name)
+
+	previous, origin := confVar.Value, confVar.Origin
+	confVar.Value, confVar.Origin = value, t.Name()
+	t.Cleanup(func() {
+		appConf.mu.Lock()
+		defer appConf.mu.Unlock()
+		confVar.Value, confVar.Origin = previous, origin
+	})
+}
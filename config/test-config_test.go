@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func (cts *ConfigTestSuite) testConfigDefaults() map[string]*Variable {
+	return map[string]*Variable{
+		"HARNESS_PORT": {
+			DefaultValue: "8080",
+			Rules:        map[string]validation.Rule{"Valid port": is.Port},
+		},
+		"HARNESS_LEVEL": {DefaultValue: "info"},
+	}
+}
+
+func (cts *ConfigTestSuite) TestNewTestConfig() {
+	cts.setEnvVars(map[string]string{"HARNESS_LEVEL": "env-level"})
+	defer func() { cts.NoError(os.Unsetenv("HARNESS_LEVEL")) }()
+
+	defaults := cts.testConfigDefaults()
+	conf := NewTestConfig(cts.T(), defaults, map[string]string{"HARNESS_PORT": "9090"})
+	cts.NoError(conf.Validate(), "Test configuration should be valid")
+	cts.Equal("9090", conf.Get("HARNESS_PORT"))
+	cts.Equal("map", conf.vars["HARNESS_PORT"].Origin)
+	cts.Equal("info", conf.Get("HARNESS_LEVEL"), "The environment should not be read")
+	cts.Empty(defaults["HARNESS_PORT"].Value, "The supplied Variables should not be modified")
+
+	envFile := cts.writeTempFile("config-harness-*.env", "HARNESS_LEVEL=debug\nHARNESS_OTHER=1\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+	cts.NoError(conf.Setup(envFile), "Test configuration should have been set up")
+	cts.Equal("debug", conf.Get("HARNESS_LEVEL"))
+	cts.Equal("env-level", os.Getenv("HARNESS_LEVEL"), "The environment should not be modified")
+	_, set := os.LookupEnv("HARNESS_OTHER")
+	cts.False(set, "The environment should not be modified")
+
+	cts.Run("SetForTest", func() {
+		conf.SetForTest(cts.T(), "HARNESS_PORT", "not-a-port")
+		cts.Equal("not-a-port", conf.Get("HARNESS_PORT"))
+		cts.Error(conf.Validate(), "Configuration should be invalid")
+	})
+	cts.Equal("9090", conf.Get("HARNESS_PORT"), "The previous value should have been restored")
+	cts.Equal("map", conf.vars["HARNESS_PORT"].Origin)
+}
+
+func (cts *ConfigTestSuite) TestIsolated() {
+	cts.setupEnvTest("HARNESS_LEVEL")
+	envFile := cts.writeTempFile("config-harness-*.env", "HARNESS_LEVEL=debug\n")
+	defer func() { cts.NoError(os.Remove(envFile), "Temp file should have been removed") }()
+
+	conf := NewConfig(cts.testConfigDefaults()).Isolated()
+	cts.NoError(conf.Setup(envFile), "Configuration should have been set up")
+	cts.Equal("debug", conf.Get("HARNESS_LEVEL"))
+	_, set := os.LookupEnv("HARNESS_LEVEL")
+	cts.False(set, "The environment should not be overloaded")
+}
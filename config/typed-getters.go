@@ -0,0 +1,137 @@
+package config
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ListSeparator separates the items of a string slice configuration value.
+const ListSeparator = ","
+
+// lookupRegistered returns a copy of the named Variable, or an error if the Variable is not registered.
+func (appConf *AppConfig) lookupRegistered(name string) (Variable, error) {
+	appConf.mu.RLock()
+	defer appConf.mu.RUnlock()
+	confVar, ok := appConf.vars[name]
+	if !ok {
+		return Variable{}, errors.Errorf("Configuration variable %s is not registered", name)
+	}
+	return *confVar, nil
+}
+
+// parseError wraps the error of parsing the Variable's value. The parse errors contain the value,
+// so for Sensitive Variables the original error is replaced.
+func parseError(confVar Variable, name, kind string, err error) error {
+	if confVar.secret() {
+		return errors.Errorf("Failed to parse %s as %s: invalid value %s", name, kind, MaskedValue)
+	}
+	return errors.Wrapf(err, "Failed to parse %s as %s", name, kind)
+}
+
+// GetInt returns the named Application Configuration Variable's value as an int64.
+// It returns an error if the Variable is not registered or its value is not a valid integer.
+func (appConf *AppConfig) GetInt(name string) (int64, error) {
+	confVar, err := appConf.lookupRegistered(name)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(confVar.Value), 10, 64)
+	if err != nil {
+		return 0, parseError(confVar, name, "integer", err)
+	}
+	return i, nil
+}
+
+// GetBool returns the named Application Configuration Variable's value as a bool.
+// It returns an error if the Variable is not registered or its value is not a valid boolean.
+func (appConf *AppConfig) GetBool(name string) (bool, error) {
+	confVar, err := appConf.lookupRegistered(name)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(confVar.Value))
+	if err != nil {
+		return false, parseError(confVar, name, "boolean", err)
+	}
+	return b, nil
+}
+
+// GetFloat returns the named Application Configuration Variable's value as a float64.
+// It returns an error if the Variable is not registered or its value is not a valid number.
+func (appConf *AppConfig) GetFloat(name string) (float64, error) {
+	confVar, err := appConf.lookupRegistered(name)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(confVar.Value), 64)
+	if err != nil {
+		return 0, parseError(confVar, name, "float", err)
+	}
+	return f, nil
+}
+
+// GetDuration returns the named Application Configuration Variable's value as a time.Duration (e.g. 1m30s).
+// It returns an error if the Variable is not registered or its value is not a valid duration.
+func (appConf *AppConfig) GetDuration(name string) (time.Duration, error) {
+	confVar, err := appConf.lookupRegistered(name)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(confVar.Value))
+	if err != nil {
+		return 0, parseError(confVar, name, "duration", err)
+	}
+	return d, nil
+}
+
+// GetURL returns the named Application Configuration Variable's value as a parsed absolute URL.
+// It returns an error if the Variable is not registered or its value is not a valid absolute URL.
+func (appConf *AppConfig) GetURL(name string) (*url.URL, error) {
+	confVar, err := appConf.lookupRegistered(name)
+	if err != nil {
+		return nil, err
+	}
+	u, err := parseAbsoluteURL(confVar.Value)
+	if err != nil {
+		return nil, parseError(confVar, name, "URL", err)
+	}
+	return u, nil
+}
+
+// GetStringSlice returns the named Application Configuration Variable's value split by ListSeparator.
+// The items are trimmed and empty items are omitted, so an empty value results in an empty slice.
+// It returns an error if the Variable is not registered.
+func (appConf *AppConfig) GetStringSlice(name string) ([]string, error) {
+	confVar, err := appConf.lookupRegistered(name)
+	if err != nil {
+		return nil, err
+	}
+	return splitList(confVar.Value), nil
+}
+
+// parseAbsoluteURL parses the raw string and checks that it has both a scheme and a host.
+func parseAbsoluteURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.Errorf("%q is not an absolute URL", raw)
+	}
+	return u, nil
+}
+
+// splitList splits a ListSeparator separated string into trimmed, non-empty items.
+func splitList(val string) []string {
+	items := []string{}
+	for _, item := range strings.Split(val, ListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"time"
+)
+
+func (cts *ConfigTestSuite) TestTypedGetters() {
+	conf := NewConfig(map[string]*Variable{
+		"INT":      {Value: " 42 "},
+		"BOOL":     {Value: "true"},
+		"FLOAT":    {Value: "3.14"},
+		"DURATION": {Value: "2s"},
+		"URL":      {Value: "postgres://db.local:5432/app"},
+		"SLICE":    {Value: "a, b,,c"},
+		"INVALID":  {Value: "not-valid"},
+	})
+
+	i, err := conf.GetInt("INT")
+	cts.NoError(err)
+	cts.Equal(int64(42), i)
+	b, err := conf.GetBool("BOOL")
+	cts.NoError(err)
+	cts.True(b)
+	f, err := conf.GetFloat("FLOAT")
+	cts.NoError(err)
+	cts.Equal(3.14, f)
+	d, err := conf.GetDuration("DURATION")
+	cts.NoError(err)
+	cts.Equal(2*time.Second, d)
+	u, err := conf.GetURL("URL")
+	cts.NoError(err)
+	cts.Equal("db.local:5432", u.Host)
+	s, err := conf.GetStringSlice("SLICE")
+	cts.NoError(err)
+	cts.Equal([]string{"a", "b", "c"}, s)
+
+	_, err = conf.GetInt("INVALID")
+	cts.EqualError(err, `Failed to parse INVALID as integer: strconv.ParseInt: parsing "not-valid": invalid syntax`)
+	_, err = conf.GetBool("INVALID")
+	cts.Error(err, "GetBool should fail on invalid value")
+	_, err = conf.GetFloat("INVALID")
+	cts.Error(err, "GetFloat should fail on invalid value")
+	_, err = conf.GetDuration("INVALID")
+	cts.Error(err, "GetDuration should fail on invalid value")
+	_, err = conf.GetURL("INVALID")
+	cts.Error(err, "GetURL should fail on relative URL")
+	_, err = conf.GetInt("MISSING")
+	cts.EqualError(err, "Configuration variable MISSING is not registered")
+}
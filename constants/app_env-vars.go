@@ -19,12 +19,55 @@ const (
 	// APP_LOG_FORMAT_ERRORS indicates if Error formating is enabled, so newlines and tabs should be converted.
 	APP_LOG_FORMAT_ERRORS = "APP_LOG_FORMAT_ERRORS"
 
+	// APP_LOG_SAMPLING_INITIAL is the number of log entries with the same level and message logged in every second,
+	// before the sampling starts. Sampling is disabled if it is not set (or 0).
+	APP_LOG_SAMPLING_INITIAL = "APP_LOG_SAMPLING_INITIAL"
+
+	// APP_LOG_SAMPLING_THEREAFTER is the ratio of the sampling, every Nth log entry is logged after the initial ones.
+	APP_LOG_SAMPLING_THEREAFTER = "APP_LOG_SAMPLING_THEREAFTER"
+
+	// APP_LOG_SAMPLING_LEVELS are the per level overrides of the sampling, like "debug=10:100,error=0".
+	APP_LOG_SAMPLING_LEVELS = "APP_LOG_SAMPLING_LEVELS"
+
+	// APP_LOG_SINKS are the destinations of the log entries in the "name[=format[:level]],..." format,
+	// like "stdout=text:debug,file=json:info,syslog=:warn,memory". The names are stdout, file, syslog and memory,
+	// the formats are json and text. The entries are written to stdout if it is not set.
+	APP_LOG_SINKS = "APP_LOG_SINKS"
+
+	// APP_LOG_FILE_PATH is the path of the file written by the file log sink.
+	APP_LOG_FILE_PATH = "APP_LOG_FILE_PATH"
+
+	// APP_LOG_FILE_MAX_SIZE is the size in megabytes, when the file of the file log sink is rotated (100 by default).
+	APP_LOG_FILE_MAX_SIZE = "APP_LOG_FILE_MAX_SIZE"
+
+	// APP_LOG_FILE_MAX_BACKUPS is the number of the rotated files kept by the file log sink (3 by default).
+	APP_LOG_FILE_MAX_BACKUPS = "APP_LOG_FILE_MAX_BACKUPS"
+
+	// APP_LOG_SYSLOG_ADDRESS is the host:port of the UDP syslog endpoint of the syslog log sink,
+	// the local syslog is used if it is not set.
+	APP_LOG_SYSLOG_ADDRESS = "APP_LOG_SYSLOG_ADDRESS"
+
+	// APP_LOG_MEMORY_SIZE is the number of the entries kept by the memory log sink (1000 by default).
+	APP_LOG_MEMORY_SIZE = "APP_LOG_MEMORY_SIZE"
+
+	// APP_AUDIT_LOG_FILE is the path of the hash-chained audit file written by the audit logger.
+	APP_AUDIT_LOG_FILE = "APP_AUDIT_LOG_FILE"
+
+	// APP_AUDIT_LOG_KEY is the secret key of the HMAC-SHA256 hash chain of the audit file.
+	APP_AUDIT_LOG_KEY = "APP_AUDIT_LOG_KEY"
+
 	// APP_DEBUG indicates if the Debug Mode is enabled.
 	APP_DEBUG = "APP_DEBUG"
 
 	// APP_DB_SECRET_NAME is the name of the entry in AWS SecretsManager,
 	// with the connection info to the application's database.
 	APP_DB_SECRET_NAME = "APP_DB_SECRET_NAME"
+
+	// APP_DB_SLOW_THRESHOLD is the duration above which the SQL statements are logged as slow, like "500ms".
+	APP_DB_SLOW_THRESHOLD = "APP_DB_SLOW_THRESHOLD"
+
+	// APP_DB_REDACT_PARAMETERS indicates if the bound parameters of the logged SQL statements should be redacted.
+	APP_DB_REDACT_PARAMETERS = "APP_DB_REDACT_PARAMETERS"
 )
 
 var (
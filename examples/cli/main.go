@@ -1,9 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -21,7 +22,8 @@ var items = []string{
 
 func main() {
 	versionCommand := cli.NewCommand("version").
-		WithAliases("ver", "-v", "--version").
+		WithAliases("ver", "--version").
+		WithShort("Prints the application's version").
 		WithTask(func(args []string) error {
 			fmt.Println(VERSION)
 			return nil
@@ -29,93 +31,81 @@ func main() {
 
 	listItemsCommand := cli.NewCommand("list").
 		WithAliases("l").
-		WithTask(listItems)
+		WithShort("List Item Store").
+		WithFlags(cli.IntFlag("limit", 0, "Maximum number of items to list").WithShort("n")).
+		WithRun(listItems)
 
 	getItemsCommand := cli.NewCommand("get").
 		WithAliases("g").
-		WithTask(getItem)
+		WithShort("Get an element from the Item Store by index").
+		WithArgs(cli.IntArg("index", "Index of the item")).
+		WithRun(getItem)
 
 	addItemsCommand := cli.NewCommand("add").
 		WithAliases("a").
-		WithTask(addItems)
+		WithShort("Add new element(s) to the Item Store").
+		WithExamples("app items add Hammer \"Flying Carpet\"").
+		WithArgs(cli.VariadicArg("items", "Items to add")).
+		WithRun(addItems)
 
 	itemsCommand := cli.NewCommand("items").
-		WithTask(cli.EndWithMessage(itemsUsage())).
+		WithShort("Manage Item Store").
+		WithExamples("app items list --limit 2").
+		WithRun(cli.EndWithHelp).
 		WithSubCommands(listItemsCommand, getItemsCommand, addItemsCommand)
 
-	rootCommand := cli.NewCommand("root").
+	rootCommand := cli.NewCommand("app").
+		WithLong("Simple CLI Example").
+		WithExamples("app items list", "app help items get").
+		WithPersistentFlags(cli.BoolFlag("verbose", "Verbose output").WithShort("v")).
+		WithMiddlewares(cli.Recover(), cli.Timing(reportTiming)).
 		WithSubCommands(versionCommand, itemsCommand).
-		WithTask(cli.EndWithMessage(usage()))
+		WithRun(cli.EndWithHelp)
+	// The completion command prints the completion script, like source <(app completion bash)
+	rootCommand.WithSubCommands(cli.NewCompletionCommand(rootCommand))
 
-	if err := rootCommand.Execute(os.Args[1:]); err != nil {
+	if err := rootCommand.ExecuteContext(context.Background(), os.Args[1:]); err != nil {
 		fmt.Println("Error:", err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
-func usage() string {
-	return `
-Simple CLI Example
-
-Usage: $ app <subcommand> <arg>
-
-Example: $ app items list
-
-Available subcommands:
-	version  - Prints the application's version
-	items    - Manage Item Store
-	`
-}
-
-func itemsUsage() string {
-	return `
-Item Store
-
-Usage: $ app items <subcommand> <args>
-
-Example: $ app items list
-
-Available subcommands:
-	list     - List Item Store
-	get      - Get an element from the Item Store by index
-	add      - Add new element(s) to the Item Store
-	`
-}
-
-func listItems(args []string) error {
+func listItems(inv *cli.Invocation) error {
 	fmt.Println("Item Store:")
 	for index, item := range items {
+		if limit := inv.Int("limit"); limit > 0 && index >= limit {
+			break
+		}
 		fmt.Printf("[%d][%s]\n", index, item)
 	}
+	if inv.Bool("verbose") {
+		fmt.Printf("%d item(s) in the Item Store\n", len(items))
+	}
 	return nil
 }
 
-func getItem(args []string) error {
-	if len(args) < 1 {
-		return errors.New("No item index provided")
-	}
-	val, err := strconv.Atoi(args[0])
-	if err != nil {
-		return errors.Wrap(err, "Failed to get item by index")
-	}
-	if val > len(items)-1 {
+func getItem(inv *cli.Invocation) error {
+	val := inv.Int("index")
+	if val < 0 || val > len(items)-1 {
 		return errors.Errorf("Index: %d is out of range: %d", val, len(items))
 	}
 	fmt.Printf("[%d][%s]\n", val, items[val])
 	return nil
 }
 
-func addItems(args []string) error {
-	if len(args) == 0 {
-		return errors.New("No item to add")
-	}
+func addItems(inv *cli.Invocation) error {
+	newItems := inv.StringSlice("items")
 	origLen := len(items)
-	items = append(items, args...)
-	if len(args) > 0 {
-		fmt.Println("Item(s) Added:")
-		for index, item := range args {
-			fmt.Printf("[%d][%s]\n", origLen+index, item)
-		}
+	items = append(items, newItems...)
+	fmt.Println("Item(s) Added:")
+	for index, item := range newItems {
+		fmt.Printf("[%d][%s]\n", origLen+index, item)
 	}
 	return nil
 }
+
+func reportTiming(inv *cli.Invocation, elapsed time.Duration) {
+	if inv.Bool("verbose") {
+		fmt.Printf("%s took %v\n", inv.CommandPath(), elapsed)
+	}
+}
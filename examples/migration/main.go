@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -55,21 +56,31 @@ func main() {
 	// versionCommand prints out the Version string
 	versionCommand := cli.NewCommand("version").
 		WithAliases("ver", "Version", "--version").
+		WithShort("Prints the Short Semantic Version string").
 		WithTask(func(args []string) error {
 			fmt.Println(version)
 			return nil
 		})
 
-	// root command prints out the usage
-	rootCommand := cli.NewCommand("root").
+	// root command prints out the help
+	rootCommand := cli.NewCommand("app").
+		WithLong(`Example Migrator CLI
+
+To spin up an empty PostgreSQL Docker container for testing execute:
+	$ docker run --name migration-test-db -e POSTGRES_PASSWORD=pass123 -p 5432:5432 -d postgres`).
+		WithExamples("app migrate info").
+		// recover from the panics and log out the executed commands with the logger
+		WithMiddlewares(cli.Recover(), logger.CommandMiddleware(log)).
 		WithSubCommands(
 			versionCommand,
 			migrationCLI.BuildMigrationCommand(),
 		).
-		WithTask(cli.EndWithMessage(usage()))
+		WithRun(cli.EndWithHelp)
 
-	if err := rootCommand.Execute(os.Args[1:]); err != nil {
-		log.WithError(err).Fatal("Failed to execute command")
+	// migrate upall stops after the running migration script on Ctrl-C
+	if err := rootCommand.ExecuteContext(context.Background(), os.Args[1:]); err != nil {
+		log.WithError(err).Error("Failed to execute command")
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
@@ -89,22 +100,3 @@ func getLogger() *logger.Logger {
 		"host":    "localhost",
 	})
 }
-
-func usage() string {
-	return `
-Example Migrator CLI
-
-To spin up an empty PostgreSQL Docker container for testing execute:
-	$ docker run --name migration-test-db -e POSTGRES_PASSWORD=pass123 -p 5432:5432 -d postgres
-
-Usage:
-	$ app <command> <subcommand>
-
-Example:
-	$ app migrate info
-
-Commands:
-	version - Prints the Short Semantic Version string
-	migrate - Manage SQL database migrations
-`
-}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	cli "github.com/toolboxcli"
+)
+
+// AuditCLI is a wrapper used to generate a CLI interface to verify the audit files.
+type AuditCLI struct {
+	output io.Writer
+	key    []byte
+}
+
+// AuditCLIOptions are the possible options for the NewAuditCLI to create an AuditCLI.
+type AuditCLIOptions struct {
+	// Output is where the results are printed. Defaults to os.Stdout.
+	Output io.Writer
+
+	// Key is the secret key of the hash chain of the audit files, like the value of APP_AUDIT_LOG_KEY.
+	Key []byte
+}
+
+// NewAuditCLI creates a new AuditCLI with the supplied AuditCLIOptions
+func NewAuditCLI(opts AuditCLIOptions) *AuditCLI {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	return &AuditCLI{output: opts.Output, key: opts.Key}
+}
+
+// BuildAuditCommand builds the audit toolbox/cli command. Which is a command line interface of the audit files.
+func (acli *AuditCLI) BuildAuditCommand() *cli.Command {
+	return cli.NewCommand("audit").
+		WithShort("Verify the audit files").
+		WithLong("This utility command is used to verify the hash chain of the audit files.").
+		WithExamples("./app audit verify /var/log/app/audit.log").
+		WithOutput(acli.output).
+		WithRun(cli.EndWithHelp).
+		WithSubCommands(
+			acli.auditVerifyCommand(),
+		)
+}
+
+func (acli *AuditCLI) auditVerifyCommand() *cli.Command {
+	return cli.NewCommand("verify").
+		WithAliases("check").
+		WithShort("Verifies the audit files, fails if any record was modified or removed").
+		WithTask(func(args []string) error {
+			if len(args) == 0 {
+				return errors.New("Audit file is missing")
+			}
+			if len(acli.key) == 0 {
+				return errors.New("Audit key is missing")
+			}
+			failed := 0
+			for _, path := range args {
+				records, err := VerifyAuditFile(path, acli.key, nil)
+				if err != nil {
+					failed++
+					fmt.Fprintf(acli.output, "TAMPERED %s: %v (%d valid records before)\n", path, err, records)
+					continue
+				}
+				fmt.Fprintf(acli.output, "OK       %s: %d records\n", path, records)
+			}
+			if failed > 0 {
+				return errors.Errorf("Audit verification failed: %d of %d file(s) are tampered or unreadable", failed, len(args))
+			}
+			return nil
+		})
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// AuditHeadSuffix is the suffix of the head file of an AuditFile, like audit.log.head.
+// The head file holds the number and the hash of the last record, so a truncated audit file is detected.
+const AuditHeadSuffix = ".head"
+
+// auditRecord is a line of the AuditFile, the event is chained to the previous line by the hashes.
+type auditRecord struct {
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+	Event    json.RawMessage `json:"event"`
+}
+
+// auditHead is the content of the head file of an AuditFile.
+type auditHead struct {
+	Records int    `json:"records"`
+	Hash    string `json:"hash"`
+}
+
+// auditHash returns the HMAC-SHA256 of the event chained to the hash of the previous record.
+func auditHash(key []byte, prevHash string, event []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(prevHash))
+	mac.Write(event)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuditFile is an append-only JSON lines file, where every written entry is a record with the HMAC-SHA256 of
+// the entry and the hash of the previous record, so any modified or removed record is detected by VerifyAuditFile.
+// Without the key the records cannot be recomputed. The number and the hash of the last record are written to
+// the head file (the path with the AuditHeadSuffix) after every record, so the truncation of the file is
+// detected too. Keep (or copy) the head file where the writers of the audit file cannot roll it back.
+type AuditFile struct {
+	mu       sync.Mutex
+	path     string
+	key      []byte
+	file     *os.File
+	records  int
+	lastHash string
+}
+
+// NewAuditFile opens (or creates) the audit file at the path, and continues the hash chain of its records
+// with the key. It fails if the records of the existing file cannot be verified with the key and its head.
+func NewAuditFile(path string, key []byte) (*AuditFile, error) {
+	if path == "" {
+		return nil, errors.New("Failed to open audit file: the path is empty")
+	}
+	if len(key) == 0 {
+		return nil, errors.Errorf("Failed to open audit file %s: the key is empty", path)
+	}
+	lastHash := ""
+	records, err := VerifyAuditFile(path, key, func(record int, hash string) {
+		lastHash = hash
+	})
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, errors.Wrapf(err, "Failed to open audit file %s", path)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open audit file %s", path)
+	}
+	return &AuditFile{path: path, key: key, file: file, records: records, lastHash: lastHash}, nil
+}
+
+// Write implements the io.Writer interface, every write is a JSON entry, which is written as a record.
+func (af *AuditFile) Write(p []byte) (int, error) {
+	event := bytes.TrimSpace(p)
+	if !json.Valid(event) {
+		return 0, errors.Errorf("Failed to write audit file %s: the entry is not JSON", af.path)
+	}
+
+	af.mu.Lock()
+	defer af.mu.Unlock()
+	if af.file == nil {
+		return 0, errors.Errorf("Failed to write audit file %s: the file is closed", af.path)
+	}
+	hash := auditHash(af.key, af.lastHash, event)
+	// the record is built by hand to keep the bytes of the event, which are hashed
+	line := []byte(`{"prev_hash":"` + af.lastHash + `","hash":"` + hash + `","event":`)
+	line = append(append(line, event...), "}\n"...)
+	if _, err := af.file.Write(line); err != nil {
+		return 0, errors.Wrapf(err, "Failed to write audit file %s", af.path)
+	}
+	af.records++
+	af.lastHash = hash
+	if err := writeAuditHead(af.path, auditHead{Records: af.records, Hash: hash}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close implements the io.Closer interface.
+func (af *AuditFile) Close() error {
+	af.mu.Lock()
+	defer af.mu.Unlock()
+	if af.file == nil {
+		return nil
+	}
+	err := af.file.Close()
+	af.file = nil
+	return errors.Wrapf(err, "Failed to close audit file %s", af.path)
+}
+
+// writeAuditHead replaces the head file of the audit file, the head is written to a temporary file first,
+// so it is never partially written.
+func writeAuditHead(path string, head auditHead) error {
+	content, err := json.Marshal(head)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to write audit head of %s", path)
+	}
+	tmp := path + AuditHeadSuffix + ".tmp"
+	if err := ioutil.WriteFile(tmp, append(content, '\n'), 0640); err != nil {
+		return errors.Wrapf(err, "Failed to write audit head of %s", path)
+	}
+	return errors.Wrapf(os.Rename(tmp, path+AuditHeadSuffix), "Failed to write audit head of %s", path)
+}
+
+// readAuditHead reads the head file of the audit file, a missing head file is an empty head.
+func readAuditHead(path string) (auditHead, error) {
+	head := auditHead{}
+	content, err := ioutil.ReadFile(path + AuditHeadSuffix)
+	if os.IsNotExist(err) {
+		return head, nil
+	}
+	if err != nil {
+		return head, errors.Wrapf(err, "Failed to read audit head of %s", path)
+	}
+	if err := json.Unmarshal(content, &head); err != nil {
+		return head, errors.Wrapf(err, "Audit head of %s is malformed", path)
+	}
+	return head, nil
+}
+
+// VerifyAuditFile verifies the hash chain of the records of the audit file with the key, and returns the number
+// of the verified records. It fails if the file has fewer records than its head, or the record of the head has
+// a different hash (the file was truncated or removed), or if it has records without a head file.
+// The optional callback is called with the number and the hash of every verified record.
+func VerifyAuditFile(path string, key []byte, verified func(record int, hash string)) (int, error) {
+	head, err := readAuditHead(path)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) && head.Records > 0 {
+		return 0, errors.Errorf("Audit file %s was truncated: 0 of %d records", path, head.Records)
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to open audit file %s", path)
+	}
+	defer file.Close()
+
+	headHash := ""
+	records, err := verifyAuditRecords(file, key, func(record int, hash string) {
+		if record == head.Records {
+			headHash = hash
+		}
+		if verified != nil {
+			verified(record, hash)
+		}
+	})
+	if err != nil {
+		return records, err
+	}
+	switch {
+	case records > 0 && head.Records == 0:
+		return records, errors.Errorf("Audit head of %s is missing", path)
+	case records < head.Records:
+		return records, errors.Errorf("Audit file %s was truncated: %d of %d records", path, records, head.Records)
+	case headHash != head.Hash:
+		return records, errors.Errorf("Audit record %d does not match the head of %s", head.Records, path)
+	}
+	return records, nil
+}
+
+// verifyAuditRecords verifies the hash chain of the records read from the reader with the key.
+func verifyAuditRecords(r io.Reader, key []byte, verified func(record int, hash string)) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	records := 0
+	prevHash := ""
+	for scanner.Scan() {
+		records++
+		record := auditRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return records - 1, errors.Wrapf(err, "Audit record %d is malformed", records)
+		}
+		if record.PrevHash != prevHash {
+			return records - 1, errors.Errorf("Audit record %d is not chained to the previous record", records)
+		}
+		if auditHash(key, prevHash, record.Event) != record.Hash {
+			return records - 1, errors.Errorf("Audit record %d was modified", records)
+		}
+		prevHash = record.Hash
+		if verified != nil {
+			verified(records, record.Hash)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return records, errors.Wrap(err, "Failed to read audit records")
+	}
+	return records, nil
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	constants "github.com/toolboxconstants"
+)
+
+// AuditMessage is the message of the audit entries.
+const AuditMessage = "audit"
+
+// Fields of the audit entries, which are always present
+const (
+	AuditUserIDField  = "user_id"
+	AuditOrgIDField   = "org_id"
+	AuditActionField  = "action"
+	AuditTargetField  = "target"
+	AuditOutcomeField = "outcome"
+	AuditDetailsField = "details"
+)
+
+// Outcomes of the audited actions
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeDenied  = "denied"
+)
+
+// AuditEvent is an audited action: who (UserID and OrgID) did what (Action) to what (Target) with which Outcome.
+type AuditEvent struct {
+	// UserID is the user who did the action, the constants.ContextKeyForUserID value of the context by default.
+	UserID string
+
+	// OrgID is the organization of the user, the constants.ContextKeyForOrgID value of the context by default.
+	OrgID string
+
+	// Action is the name of the action, like "user.delete".
+	Action string
+
+	// Target is the identifier of the object of the action.
+	Target string
+
+	// Outcome is the outcome of the action, like AuditOutcomeSuccess.
+	Outcome string
+
+	// Details are optional details of the action.
+	Details map[string]interface{}
+
+	// Time is the time of the action, the current time by default.
+	Time time.Time
+}
+
+// fields returns the fields of the audit entry, the missing user and org ids are taken from the context.
+func (e AuditEvent) fields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{
+		AuditUserIDField:  e.UserID,
+		AuditOrgIDField:   e.OrgID,
+		AuditActionField:  e.Action,
+		AuditTargetField:  e.Target,
+		AuditOutcomeField: e.Outcome,
+	}
+	if e.UserID == "" {
+		fields[AuditUserIDField] = contextString(ctx, constants.ContextKeyForUserID)
+	}
+	if e.OrgID == "" {
+		fields[AuditOrgIDField] = contextString(ctx, constants.ContextKeyForOrgID)
+	}
+	if len(e.Details) > 0 {
+		fields[AuditDetailsField] = e.Details
+	}
+	return fields
+}
+
+// contextString returns the value of the key in the context as a string, or an empty string.
+func contextString(ctx context.Context, key constants.ContextKey) string {
+	if val := ctx.Value(key); val != nil {
+		return fmt.Sprint(val)
+	}
+	return ""
+}
+
+// AuditLogger writes the AuditEvents to an audit channel, which is separate from the application logs.
+// The entries have the default fields and the context fields of the Logger, plus the fixed fields of the events.
+type AuditLogger struct {
+	mu        sync.Mutex
+	log       *Logger
+	formatter logrus.Formatter
+	writer    io.Writer
+}
+
+// NewAuditLogger creates a new AuditLogger writing the events as JSON lines to the writer
+// (usually an AuditFile), with the default fields.
+func NewAuditLogger(writer io.Writer, defaultFields logrus.Fields) *AuditLogger {
+	return &AuditLogger{
+		log:       NewLogger(logrus.New(), defaultFields),
+		formatter: &logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano},
+		writer:    writer,
+	}
+}
+
+// NewAuditLoggerFromConfiguration creates an AuditLogger writing to the AuditFile at the APP_AUDIT_LOG_FILE path
+// chained with the APP_AUDIT_LOG_KEY key, with the service, version, env and host default fields like NewCommonLoggerFromConfiguration.
+func NewAuditLoggerFromConfiguration(serviceName, serviceVersion string, config configGetter) (*AuditLogger, error) {
+	file, err := NewAuditFile(config.Get(constants.APP_AUDIT_LOG_FILE), []byte(config.Get(constants.APP_AUDIT_LOG_KEY)))
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditLogger(file, logrus.Fields{
+		"service": serviceName,
+		"version": serviceVersion,
+		"env":     config.Get(constants.APP_ENV),
+		"host":    config.Hostname(),
+	}), nil
+}
+
+// Log writes the event. Unlike the application logs, the failures are returned,
+// so the audited action can be refused if it cannot be audited.
+func (al *AuditLogger) Log(ctx context.Context, event AuditEvent) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	entry := al.log.ForContext(ctx).WithFields(event.fields(ctx)).WithTime(event.Time)
+	entry.Level = logrus.InfoLevel
+	entry.Message = AuditMessage
+
+	serialized, err := al.formatter.Format(entry)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to format audit event %s", event.Action)
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	if _, err := al.writer.Write(serialized); err != nil {
+		return errors.Wrapf(err, "Failed to write audit event %s", event.Action)
+	}
+	return nil
+}
+
+// Close closes the writer of the AuditLogger, if it is an io.Closer.
+func (al *AuditLogger) Close() error {
+	if closer, ok := al.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	config "github.com/toolboxconfig"
+	constants "github.com/toolboxconstants"
+)
+
+var testAuditKey = []byte("test-audit-key")
+
+func (ls *LoggerSuite) TestAuditLogger() {
+	path := filepath.Join(ls.T().TempDir(), "audit.log")
+	conf := config.NewConfig(map[string]*config.Variable{
+		constants.APP_AUDIT_LOG_FILE: {DefaultValue: path},
+		constants.APP_AUDIT_LOG_KEY:  {DefaultValue: string(testAuditKey), Sensitive: true},
+		constants.APP_ENV:            {DefaultValue: constants.ENV_TEST},
+	})
+	ls.NoError(conf.Setup(), "Configuration should have been set up")
+	audit, err := NewAuditLoggerFromConfiguration("test-service", "v1.2.3", conf)
+	ls.NoError(err, "Audit logger should have been created")
+
+	ctx := context.WithValue(context.Background(), constants.ContextKeyForUserID, "user-1")
+	ctx = context.WithValue(ctx, constants.ContextKeyForOrgID, 42)
+	ls.NoError(audit.Log(ctx, AuditEvent{Action: "user.delete", Target: "user-2", Outcome: AuditOutcomeSuccess}))
+	ls.NoError(audit.Log(context.Background(), AuditEvent{
+		UserID:  "admin",
+		Action:  "report.export",
+		Target:  "report-1",
+		Outcome: AuditOutcomeDenied,
+		Details: map[string]interface{}{"format": "csv"},
+		Time:    time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+	ls.NoError(audit.Close())
+
+	records, err := VerifyAuditFile(path, testAuditKey, nil)
+	ls.NoError(err, "Audit file should be valid")
+	ls.Equal(2, records)
+
+	data, err := ioutil.ReadFile(path)
+	ls.NoError(err, "Audit file should exist")
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	record := auditRecord{}
+	ls.NoError(json.Unmarshal([]byte(lines[0]), &record))
+	event := map[string]interface{}{}
+	ls.NoError(json.Unmarshal(record.Event, &event))
+	ls.Equal("user-1", event[AuditUserIDField], "The user id should be taken from the context")
+	ls.Equal("42", event[AuditOrgIDField], "The org id should be taken from the context")
+	ls.Equal("user.delete", event[AuditActionField])
+	ls.Equal(AuditOutcomeSuccess, event[AuditOutcomeField])
+	ls.Equal("test-service", event["service"])
+	ls.Equal(AuditMessage, event["msg"])
+
+	ls.NoError(json.Unmarshal([]byte(lines[1]), &record))
+	ls.NoError(json.Unmarshal(record.Event, &event))
+	ls.Equal("", event[AuditOrgIDField], "The fields of the schema should always be present")
+	ls.Equal(map[string]interface{}{"format": "csv"}, event[AuditDetailsField])
+	ls.Equal("2021-01-02T03:04:05Z", event["time"])
+
+	_, err = NewAuditFile(path, []byte("other-key"))
+	ls.ErrorContains(err, "Audit record 1 was modified", "Audit file should not be continued with another key")
+	file, err := NewAuditFile(path, testAuditKey)
+	ls.NoError(err, "Audit file should have been reopened")
+	ls.NoError(NewAuditLogger(file, logrus.Fields{}).Log(ctx, AuditEvent{Action: "user.create", Outcome: AuditOutcomeFailure}))
+	ls.NoError(file.Close())
+	records, err = VerifyAuditFile(path, testAuditKey, nil)
+	ls.NoError(err, "The hash chain should have been continued")
+	ls.Equal(3, records)
+}
+
+func (ls *LoggerSuite) TestAuditFileTampering() {
+	path := filepath.Join(ls.T().TempDir(), "audit.log")
+	_, err := NewAuditFile(path, nil)
+	ls.Error(err, "Audit file should require a key")
+	file, err := NewAuditFile(path, testAuditKey)
+	ls.NoError(err, "Audit file should have been created")
+	audit := NewAuditLogger(file, nil)
+	for _, target := range []string{"user-1", "user-2", "user-3"} {
+		ls.NoError(audit.Log(context.Background(), AuditEvent{UserID: "admin", Action: "user.delete", Target: target}))
+	}
+	ls.NoError(audit.Close())
+	data, err := ioutil.ReadFile(path)
+	ls.NoError(err, "Audit file should exist")
+
+	output := &bytes.Buffer{}
+	cmd := NewAuditCLI(AuditCLIOptions{Output: output, Key: testAuditKey}).BuildAuditCommand()
+	ls.NoError(cmd.Validate(), "The subcommands should not share names or aliases")
+	ls.NoError(cmd.Execute([]string{"verify", path}))
+	ls.Contains(output.String(), "OK       "+path+": 3 records")
+
+	// the chain of the modified event is recomputed without the key
+	recomputed := &bytes.Buffer{}
+	prevHash := ""
+	for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		record := auditRecord{}
+		ls.NoError(json.Unmarshal([]byte(line), &record))
+		if i == 1 {
+			record.Event = json.RawMessage(strings.Replace(string(record.Event), "user-2", "user-9", 1))
+		}
+		record.PrevHash, record.Hash = prevHash, auditHash(nil, prevHash, record.Event)
+		prevHash = record.Hash
+		line, err := json.Marshal(record)
+		ls.NoError(err)
+		recomputed.Write(append(line, '\n'))
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	for name, tampered := range map[string]string{
+		"Audit record 2 was modified":                           lines[0] + strings.Replace(lines[1], "user-2", "user-9", 1) + lines[2],
+		"Audit record 2 is not chained to the previous record":  lines[0] + lines[2],
+		"Audit record 1 is malformed":                           "not json\n" + lines[1],
+		"Audit record 1 was modified":                           recomputed.String(),
+		"Audit file " + path + " was truncated: 2 of 3 records": lines[0] + lines[1],
+		"Audit file " + path + " was truncated: 0 of 3 records": "",
+	} {
+		ls.NoError(ioutil.WriteFile(path, []byte(tampered), 0600))
+		_, err = VerifyAuditFile(path, testAuditKey, nil)
+		ls.ErrorContains(err, name, "Tampering should have been detected")
+
+		output.Reset()
+		ls.Error(cmd.Execute([]string{"verify", path}), "Verification should fail")
+		ls.Contains(output.String(), "TAMPERED "+path)
+		_, err = NewAuditFile(path, testAuditKey)
+		ls.Error(err, "Tampered audit file should not be continued")
+	}
+
+	ls.NoError(os.Remove(path))
+	_, err = VerifyAuditFile(path, testAuditKey, nil)
+	ls.ErrorContains(err, "Audit file "+path+" was truncated: 0 of 3 records", "Removed audit file should be detected")
+	ls.NoError(ioutil.WriteFile(path, data, 0600))
+	ls.NoError(os.Remove(path + AuditHeadSuffix))
+	_, err = VerifyAuditFile(path, testAuditKey, nil)
+	ls.ErrorContains(err, "Audit head of "+path+" is missing")
+	ls.EqualError(NewAuditCLI(AuditCLIOptions{Output: output}).BuildAuditCommand().Execute([]string{"verify", path}),
+		"Audit key is missing")
+
+	ls.Error(cmd.Execute([]string{"verify"}), "Audit file should be required")
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/toolboxcli"
+)
+
+// CommandField is the field of the path of the executed command, like "app migrate upall"
+const CommandField = "command"
+
+// CommandMiddleware returns a toolbox/cli Middleware, which attaches the logger (with the command field) to the
+// context of the invocation, and logs out the executed command with the elapsed time and its error.
+// Add it to the root command, so every subcommand is logged, like the LoggingMiddleware logs the requests.
+func CommandMiddleware(logger *Logger) cli.Middleware {
+	return func(next cli.Handler) cli.Handler {
+		return func(inv *cli.Invocation) error {
+			// register the time when the command was started
+			start := time.Now()
+			commandLogger := logger.child(logrus.Fields{CommandField: inv.CommandPath()})
+			// call next middleware with the logger attached to the context
+			inv.SetContext(commandLogger.WithContext(inv.Context()))
+			err := next(inv)
+			// when the call to next returns, we log out the command and the elapsed time
+			fields := logrus.Fields{
+				"duration": time.Since(start),
+			}
+			if err != nil {
+				commandLogger.WithError(err).WithFields(fields).Error("Command failed")
+			} else {
+				commandLogger.WithFields(fields).Info("Command")
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	cli "github.com/toolboxcli"
+)
+
+func (ls *LoggerSuite) TestCommandMiddleware() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, logrus.Fields{"service": "test-service"})
+
+	cmd := cli.NewCommand("app").
+		WithMiddlewares(CommandMiddleware(testLogger)).
+		WithSubCommands(
+			cli.NewCommand("ok").WithContextTask(func(ctx context.Context, args []string) error {
+				FromContext(ctx).Entry().Info("Task")
+				return nil
+			}),
+			cli.NewCommand("fail").WithTask(func(args []string) error {
+				return errors.New("Task failed")
+			}),
+		)
+
+	ls.NoError(cmd.Execute([]string{"ok"}))
+	entries := hook.AllEntries()
+	ls.Len(entries, 2)
+	ls.Equal("app ok", entries[0].Data[CommandField], "The logger in the context should have the command field")
+	ls.Equal("Command", entries[1].Message)
+	ls.Equal(logrus.InfoLevel, entries[1].Level)
+	ls.Contains(entries[1].Data, "duration")
+
+	hook.Reset()
+	ls.EqualError(cmd.Execute([]string{"fail"}), "Task failed")
+	ls.Equal("Command failed", hook.LastEntry().Message)
+	ls.Equal(logrus.ErrorLevel, hook.LastEntry().Level)
+	ls.Equal("app fail", hook.LastEntry().Data[CommandField])
+}
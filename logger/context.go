@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	constants "github.com/toolboxconstants"
+)
+
+// ContextFieldKeys are the context keys, whose values are added to the log entries as fields
+// (named after the keys) by ForContext and FromContext.
+var ContextFieldKeys = []constants.ContextKey{
+	constants.ContextKeyForAutobinckID,
+	constants.ContextKeyForUserID,
+	constants.ContextKeyForOrgID,
+	constants.ContextKeyForOrgAutobinckID,
+	constants.ContextKeyForCustomerIdentifier,
+	constants.ContextKeyForTravelerIdentifier,
+	constants.ContextKeyForLabelAutobinckID,
+	constants.ContextKeyForLabelKey,
+	constants.ContextKeyForSource,
+}
+
+// loggerContextKey is the key of the Logger attached to a context.
+type loggerContextKey struct{}
+
+// WithContext returns a copy of the context carrying the Logger, which can be retrieved by FromContext.
+func (l *Logger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext returns a child of the Logger attached to the context with WithContext, with the values
+// of the ContextFieldKeys and the ids of the OpenTelemetry span stored in the context as default fields.
+// If no Logger is attached, a child of a Logger around the standard logrus logger is returned.
+func FromContext(ctx context.Context) *Logger {
+	l, ok := ctx.Value(loggerContextKey{}).(*Logger)
+	if !ok {
+		l = NewLogger(logrus.StandardLogger(), logrus.Fields{})
+	}
+	return l.ForContext(ctx)
+}
+
+// ForContext creates a child logger with the values of the ContextFieldKeys stored in the context as default fields.
+// If the context carries an OpenTelemetry span, its trace_id, span_id and trace_flags are added too.
+func (l *Logger) ForContext(ctx context.Context) *Logger {
+	fields := traceFields(ctx)
+	for _, key := range ContextFieldKeys {
+		if val := ctx.Value(key); val != nil {
+			fields[string(key)] = val
+		}
+	}
+	return l.child(fields)
+}
+
+// child creates a new logger with the loggers default FieldLogger, fields and gorm config,
+// and adds the supplied fields to the default fields.
+func (l *Logger) child(fields logrus.Fields) *Logger {
+	newFields := logrus.Fields{}
+	for key, value := range l.defaultFields {
+		newFields[key] = value
+	}
+	for key, value := range fields {
+		newFields[key] = value
+	}
+	newLogger := NewLogger(l.log, newFields)
+	*newLogger.gormConf = *l.gormConf
+	newLogger.redactParameters = l.redactParameters
+	newLogger.sampler = l.sampler
+	newLogger.redactor = l.redactor
+	newLogger.levels = l.levels
+	newLogger.sinks = l.sinks
+	newLogger.core = l.core
+	return newLogger
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	constants "github.com/toolboxconstants"
+)
+
+func (ls *LoggerSuite) TestFromContext() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, logrus.Fields{"service": "test-service"})
+
+	ctx := testLogger.WithContext(context.Background())
+	ctx = context.WithValue(ctx, constants.ContextKeyForUserID, "user-1")
+	ctx = context.WithValue(ctx, constants.ContextKeyForOrgID, 42)
+	ctx = context.WithValue(ctx, constants.ContextKeyForEmail, "john@example.com")
+
+	FromContext(ctx).WithField("extra-field", "extra-value").Info("Info msg")
+	ls.Equal(logrus.Fields{
+		"service":     "test-service",
+		"user_id":     "user-1",
+		"org_id":      42,
+		"extra-field": "extra-value",
+	}, hook.LastEntry().Data, "Context fields should have been added to the log entry")
+	ls.Equal(logrus.Fields{"service": "test-service"}, testLogger.defaultFields, "The parent logger should not be modified")
+
+	componentLog := FromContext(ctx).NewComponentLogger("test-component")
+	ls.Equal("user-1", componentLog.defaultFields["user_id"], "Child loggers should keep the context fields")
+}
+
+func (ls *LoggerSuite) TestFromContextWithoutLogger() {
+	ctx := context.WithValue(context.Background(), constants.ContextKeyForCustomerIdentifier, "customer-1")
+	log := FromContext(ctx)
+	ls.NotNil(log, "A logger should be returned without an attached logger")
+	ls.Equal(logrus.Fields{"customer_identifier": "customer-1"}, log.defaultFields)
+}
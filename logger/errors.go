@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Fields of the structured errors added by WithError next to the flat "error" field.
+const (
+	ErrorMessageField     = "error.message"
+	ErrorTypeField        = "error.type"
+	ErrorStackField       = "error.stack"
+	ErrorCausesField      = "error.causes"
+	ErrorMessageCodeField = "error.messageCode"
+)
+
+// MessageCoder is implemented by the typed application errors carrying a message code (like models.AppError).
+type MessageCoder interface {
+	GetMessageCode() string
+}
+
+// stackTracer is implemented by the pkg/errors errors with stack trace.
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// errorFields returns the structured fields of the error. The type is the type of the root cause,
+// the stack is the stack trace of the innermost pkg/errors error with stack trace (closest to the root cause),
+// and the causes are the distinct messages of the chain (unwrapped by errors.Unwrap, supporting %w too).
+func errorFields(err error) logrus.Fields {
+	fields := logrus.Fields{
+		ErrorMessageField: err.Error(),
+	}
+
+	causes := []string{}
+	var stack errors.StackTrace
+	root := err
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		root = cause
+		if len(causes) == 0 || causes[len(causes)-1] != cause.Error() {
+			causes = append(causes, cause.Error())
+		}
+		if tracer, ok := cause.(stackTracer); ok {
+			stack = tracer.StackTrace()
+		}
+	}
+	fields[ErrorTypeField] = fmt.Sprintf("%T", root)
+	fields[ErrorCausesField] = causes
+	if stack != nil {
+		fields[ErrorStackField] = stackFrames(stack)
+	}
+
+	var coder MessageCoder
+	if errors.As(err, &coder) && coder.GetMessageCode() != "" {
+		fields[ErrorMessageCodeField] = coder.GetMessageCode()
+	}
+	return fields
+}
+
+// stackFrames returns the frames of the stack trace as "function file:line" strings.
+func stackFrames(stack errors.StackTrace) []string {
+	frames := make([]string, 0, len(stack))
+	for _, frame := range stack {
+		pc := uintptr(frame) - 1
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			frames = append(frames, "unknown")
+			continue
+		}
+		file, line := fn.FileLine(pc)
+		frames = append(frames, fmt.Sprintf("%s %s:%d", fn.Name(), file, line))
+	}
+	return frames
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	constants "github.com/toolboxconstants"
+	models "github.com/toolboxmodels"
+)
+
+func (ls *LoggerSuite) TestWithErrorStructured() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, nil)
+
+	err := fmt.Errorf("Failed to handle request: %w", getSomething())
+	testLogger.WithError(err).Error("Something went wrong")
+	data := hook.LastEntry().Data
+	ls.Equal("Failed to handle request: Cannot get something: Test Error", data[ErrorMessageField])
+	ls.Equal("*errors.fundamental", data[ErrorTypeField], "The type of the root cause should have been added")
+	ls.Equal([]string{
+		"Failed to handle request: Cannot get something: Test Error",
+		"Cannot get something: Test Error",
+		"Test Error",
+	}, data[ErrorCausesField], "The cause chain should have been added")
+	stack, ok := data[ErrorStackField].([]string)
+	ls.True(ok, "The stack should be a list of frames")
+	ls.Contains(stack[0], "github.com/toolboxlogger.getError", "The stack of the root cause should have been added")
+	ls.Contains(stack[0], "logger_test.go:")
+	ls.NotContains(data, ErrorMessageCodeField)
+}
+
+func (ls *LoggerSuite) TestWithErrorMessageCode() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, nil)
+
+	appErr := models.AppError{ErrorResponseFormat: models.ErrorResponseFormat{
+		Code: 401, Message: "Unauthorized", MessageCode: constants.UnauthorizedAccess,
+	}}
+	testLogger.WithError(errors.Wrap(appErr, "Failed to authorize")).Error("Something went wrong")
+	data := hook.LastEntry().Data
+	ls.Equal(constants.UnauthorizedAccess, data[ErrorMessageCodeField], "The message code should have been added")
+	ls.Equal("models.AppError", data[ErrorTypeField])
+
+	testLogger.WithError(io.EOF).Error("Something went wrong")
+	data = hook.LastEntry().Data
+	ls.Equal("*errors.errorString", data[ErrorTypeField])
+	ls.Equal([]string{"EOF"}, data[ErrorCausesField])
+	ls.NotContains(data, ErrorStackField, "Errors without stack trace should not have stack")
+}
+
+func (ls *LoggerSuite) TestErrorResponseFormatField() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, nil)
+
+	response := models.ErrorResponseFormat{Code: 401, Message: "Unauthorized", MessageCode: constants.UnauthorizedAccess}
+	testLogger.WithField("response", response).Error("Something went wrong")
+	content, err := (&logrus.JSONFormatter{}).Format(hook.LastEntry())
+	ls.NoError(err)
+	ls.Contains(string(content),
+		`"response":{"code":401,"message":"Unauthorized","messageCode":"`+constants.UnauthorizedAccess+`"}`,
+		"The ErrorResponseFormat should be logged with its fields")
+}
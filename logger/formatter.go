@@ -0,0 +1,21 @@
+package logger
+
+import "github.com/sirupsen/logrus"
+
+// Format implements the logrus.Formatter interface, it adapts the entries of the logrus.Logger (after its hooks)
+// to the pipeline, and returns nothing for the output of the logrus.Logger (unless the pipeline keeps
+// the original formatter).
+func (p *pipeline) Format(entry *logrus.Entry) ([]byte, error) {
+	return p.write(entry, false)
+}
+
+// baseLogger returns the logrus.Logger behind the FieldLogger, or nil if it is unknown.
+func baseLogger(log logrus.FieldLogger) *logrus.Logger {
+	switch l := log.(type) {
+	case *logrus.Logger:
+		return l
+	case *logrus.Entry:
+		return l.Logger
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	constants "github.com/toolboxconstants"
+	gormLog "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// Fields of the SQL statements logged by the gorm logger
+const (
+	GormSQLField      = "sql"
+	GormRowsField     = "rows"
+	GormDurationField = "duration_ms"
+	GormCallerField   = "caller"
+)
+
+// sqlParameterPattern matches the string and the numeric literals (the bound parameters) of the SQL statements.
+var sqlParameterPattern = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+
+// GormOptions are the possible options of the gorm loggers created by NewGormLogger.
+type GormOptions struct {
+	// SlowThreshold is the duration above which the SQL statements are logged as slow on warn level,
+	// 0 disables the slow statement warnings.
+	SlowThreshold time.Duration
+
+	// IgnoreRecordNotFoundError disables the logging of the gorm.ErrRecordNotFound errors.
+	IgnoreRecordNotFoundError bool
+
+	// RedactParameters replaces the bound parameters of the SQL statements with ?.
+	RedactParameters bool
+}
+
+// SetGormOptions sets the options of the gorm loggers created by NewGormLogger,
+// and by the component and context loggers created after it.
+func (l *Logger) SetGormOptions(opts GormOptions) {
+	l.gormConf.SlowThreshold = opts.SlowThreshold
+	l.gormConf.IgnoreRecordNotFoundError = opts.IgnoreRecordNotFoundError
+	l.redactParameters = opts.RedactParameters
+}
+
+// gormOptionsFromConfiguration returns the GormOptions configured by the APP_DB_SLOW_THRESHOLD
+// and APP_DB_REDACT_PARAMETERS variables. Invalid values are ignored.
+func gormOptionsFromConfiguration(l *Logger, config configGetter) GormOptions {
+	opts := GormOptions{SlowThreshold: l.gormConf.SlowThreshold}
+	if threshold, err := time.ParseDuration(config.Get(constants.APP_DB_SLOW_THRESHOLD)); err == nil {
+		opts.SlowThreshold = threshold
+	}
+	opts.RedactParameters, _ = strconv.ParseBool(config.Get(constants.APP_DB_REDACT_PARAMETERS))
+	return opts
+}
+
+// gormLogger is a gorm/logger.Interface, which writes the SQL statements as structured entries
+// with the logger of their contexts.
+type gormLogger struct {
+	log              *Logger
+	component        string
+	conf             gormLog.Config
+	redactParameters bool
+}
+
+// logger returns the component logger of the Logger attached to the context (or of the Logger of the gorm logger)
+// with the context fields and the trace fields of the context.
+func (gl *gormLogger) logger(ctx context.Context) *Logger {
+	if ctx == nil {
+		return gl.log
+	}
+	if ctxLogger, ok := ctx.Value(loggerContextKey{}).(*Logger); ok {
+		return ctxLogger.NewComponentLogger(gl.component).ForContext(ctx)
+	}
+	return gl.log.ForContext(ctx)
+}
+
+// sqlFields returns the fields of the SQL statement, redacted if it is enabled.
+func (gl *gormLogger) sqlFields(log *Logger, fc func() (string, int64), elapsed time.Duration, caller string) logrus.Fields {
+	sql, rows := fc()
+	if gl.redactParameters {
+		sql = sqlParameterPattern.ReplaceAllString(sql, "?")
+	}
+	if log.redactor != nil {
+		sql = log.redactor.redactSQL(sql)
+	}
+	fields := logrus.Fields{
+		GormSQLField:      sql,
+		GormDurationField: float64(elapsed.Nanoseconds()) / 1e6,
+		GormCallerField:   caller,
+	}
+	if rows != -1 {
+		fields[GormRowsField] = rows
+	}
+	return fields
+}
+
+// LogMode returns a copy of the logger with the supplied log level
+func (gl *gormLogger) LogMode(level gormLog.LogLevel) gormLog.Interface {
+	newLogger := *gl
+	newLogger.conf.LogLevel = level
+	return &newLogger
+}
+
+// Info logs the message on info level
+func (gl *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if gl.conf.LogLevel >= gormLog.Info {
+		gl.logger(ctx).WithField(GormCallerField, utils.FileWithLineNum()).Infof(msg, data...)
+	}
+}
+
+// Warn logs the message on warn level
+func (gl *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if gl.conf.LogLevel >= gormLog.Warn {
+		gl.logger(ctx).WithField(GormCallerField, utils.FileWithLineNum()).Warnf(msg, data...)
+	}
+}
+
+// Error logs the message on error level
+func (gl *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if gl.conf.LogLevel >= gormLog.Error {
+		gl.logger(ctx).WithField(GormCallerField, utils.FileWithLineNum()).Errorf(msg, data...)
+	}
+}
+
+// Trace logs the SQL statement with its duration and the number of affected rows,
+// on error level if it failed, on warn level if it was slow, and on info level otherwise.
+func (gl *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if gl.conf.LogLevel <= gormLog.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && gl.conf.LogLevel >= gormLog.Error &&
+		(!errors.Is(err, gormLog.ErrRecordNotFound) || !gl.conf.IgnoreRecordNotFoundError):
+		log := gl.logger(ctx)
+		log.WithError(err).WithFields(gl.sqlFields(log, fc, elapsed, utils.FileWithLineNum())).Error("SQL error")
+	case gl.conf.SlowThreshold != 0 && elapsed > gl.conf.SlowThreshold && gl.conf.LogLevel >= gormLog.Warn:
+		log := gl.logger(ctx)
+		log.WithFields(gl.sqlFields(log, fc, elapsed, utils.FileWithLineNum())).Warnf("Slow SQL >= %v", gl.conf.SlowThreshold)
+	case gl.conf.LogLevel == gormLog.Info:
+		log := gl.logger(ctx)
+		log.WithFields(gl.sqlFields(log, fc, elapsed, utils.FileWithLineNum())).Info("SQL")
+	}
+}
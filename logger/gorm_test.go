@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	config "github.com/toolboxconfig"
+	constants "github.com/toolboxconstants"
+	gormLog "gorm.io/gorm/logger"
+)
+
+func (ls *LoggerSuite) TestGormLoggerTrace() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, logrus.Fields{"service": "test-service"})
+	testLogger.SetGormOptions(GormOptions{SlowThreshold: 100 * time.Millisecond, IgnoreRecordNotFoundError: true})
+	gormLogger := testLogger.NewGormLogger("GORM")
+
+	gormLogger.Trace(context.TODO(), time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	ls.Equal(logrus.InfoLevel, hook.LastEntry().Level)
+	ls.Equal("SQL", hook.LastEntry().Message)
+	ls.Equal("SELECT 1", hook.LastEntry().Data[GormSQLField])
+	ls.Equal(int64(1), hook.LastEntry().Data[GormRowsField])
+	ls.Contains(hook.LastEntry().Data, GormDurationField)
+	ls.Contains(hook.LastEntry().Data[GormCallerField], "gorm_test.go", "The caller should be the caller of the gorm logger")
+	ls.Equal("GORM", hook.LastEntry().Data["component"])
+
+	gormLogger.Trace(context.TODO(), time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 2", -1 }, nil)
+	ls.Equal(logrus.WarnLevel, hook.LastEntry().Level)
+	ls.Equal("Slow SQL >= 100ms", hook.LastEntry().Message)
+	ls.NotContains(hook.LastEntry().Data, GormRowsField, "Unknown number of rows should not be logged")
+
+	gormLogger.Trace(context.TODO(), time.Now(), func() (string, int64) { return "SELECT 3", 0 }, errors.New("Deadlock"))
+	ls.Equal(logrus.ErrorLevel, hook.LastEntry().Level)
+	ls.Equal("Deadlock", hook.LastEntry().Data[ErrorMessageField])
+	ls.Equal("SELECT 3", hook.LastEntry().Data[GormSQLField])
+
+	gormLogger.Trace(context.TODO(), time.Now(), func() (string, int64) { return "SELECT 4", 0 }, gormLog.ErrRecordNotFound)
+	ls.Equal(logrus.InfoLevel, hook.LastEntry().Level, "Ignored errors should not be logged as errors")
+
+	hook.Reset()
+	gormLogger.LogMode(gormLog.Silent).Trace(context.TODO(), time.Now(), func() (string, int64) { return "SELECT 5", 0 }, nil)
+	gormLogger.LogMode(gormLog.Warn).Info(context.TODO(), "Info %s", "msg")
+	ls.Empty(hook.AllEntries(), "Disabled levels should not be logged")
+
+	gormLogger.Warn(context.TODO(), "Warn %s", "msg")
+	ls.Equal("Warn msg", hook.LastEntry().Message)
+	ls.Contains(hook.LastEntry().Data[GormCallerField], "gorm_test.go")
+}
+
+func (ls *LoggerSuite) TestGormLoggerContext() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, logrus.Fields{"service": "test-service"})
+	testLogger.SetGormOptions(GormOptions{RedactParameters: true})
+	gormLogger := testLogger.NewGormLogger("GORM")
+
+	requestLogger := testLogger.child(logrus.Fields{"request_id": "request-1"})
+	ctx := requestLogger.WithContext(context.TODO())
+	gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+		return `SELECT * FROM "users" WHERE "name" = 'O''Brien' AND "age" > 30.5 AND "col_2" = 1 LIMIT 10`, 1
+	}, nil)
+	ls.Equal(`SELECT * FROM "users" WHERE "name" = ? AND "age" > ? AND "col_2" = ? LIMIT ?`, hook.LastEntry().Data[GormSQLField],
+		"The parameters should have been redacted")
+	ls.Equal("request-1", hook.LastEntry().Data["request_id"], "The logger of the context should have been used")
+	ls.Equal("GORM", hook.LastEntry().Data["component"])
+}
+
+func (ls *LoggerSuite) TestGormOptionsFromConfiguration() {
+	conf := config.NewConfig(map[string]*config.Variable{
+		constants.APP_DB_SLOW_THRESHOLD:    {DefaultValue: "500ms"},
+		constants.APP_DB_REDACT_PARAMETERS: {DefaultValue: "true"},
+	})
+	ls.NoError(conf.Setup(), "Configuration should have been set up")
+
+	commonLog := NewCommonLoggerFromConfiguration("test-service", "v1.2.3", conf)
+	gormLogger := commonLog.NewComponentLogger("other").NewGormLogger("GORM").(*gormLogger)
+	ls.Equal(500*time.Millisecond, gormLogger.conf.SlowThreshold)
+	ls.True(gormLogger.redactParameters)
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pipeline is the core of the Logger: it drops the entries disabled by the LevelController or the Sampler,
+// then passes them to the slog.Handler of the Logger and writes them to the sinks. The records of the slog API
+// (see Logger.Handler) enter the pipeline directly, the logrus.Logger behind the Logger is the adapter
+// of the entries created by Entry, WithField, WithFields and WithError (see Format).
+// The pipeline of a logrus.FieldLogger supplied to NewLogger keeps its formatter and output for the entries,
+// while no slog.Handler and no sinks are set.
+type pipeline struct {
+	mu       sync.RWMutex
+	base     *logrus.Logger
+	handler  slog.Handler
+	sinks    []*Sink
+	levels   *LevelController
+	sampler  *Sampler
+	redactor *Redactor
+
+	// formatter is the original formatter of the logrus.Logger, nil for the Loggers backed by a slog.Handler
+	formatter logrus.Formatter
+}
+
+// newPipeline makes the pipeline with the slog.Handler the core of the logrus.Logger, which only adapts the
+// entries to records after that, its output is discarded.
+func newPipeline(base *logrus.Logger, handler slog.Handler) *pipeline {
+	p := &pipeline{base: base, handler: handler}
+	base.SetOutput(ioutil.Discard)
+	base.SetFormatter(p)
+	return p
+}
+
+// empty reports if the pipeline of a logrus.FieldLogger does nothing else than its original formatter,
+// the caller must hold the lock.
+func (p *pipeline) empty() bool {
+	return p.formatter != nil && p.handler == nil && len(p.sinks) == 0 && p.levels == nil && p.sampler == nil
+}
+
+// write passes the entry to the slog.Handler and writes it to the sinks, unless it is dropped.
+// The entries of the slog API are redacted here, the entries of the logrus.Logger are redacted by its hooks.
+// It returns the entry formatted by the original formatter of a logrus.FieldLogger, if it has no other outputs.
+func (p *pipeline) write(entry *logrus.Entry, redact bool) ([]byte, error) {
+	p.mu.RLock()
+	handler, sinks, levels, sampler, redactor, formatter := p.handler, p.sinks, p.levels, p.sampler, p.redactor, p.formatter
+	p.mu.RUnlock()
+
+	if levels != nil && !levels.enabled(entry) {
+		return nil, nil
+	}
+	if sampler != nil && !sampler.Sample(entry) {
+		return nil, nil
+	}
+	if redact && redactor != nil {
+		// Fire cannot fail
+		_ = redactor.Fire(entry)
+	}
+
+	// the formatters write into the buffer of the entry, which would be shared by the sinks
+	buffer := entry.Buffer
+	entry.Buffer = nil
+	for _, sink := range sinks {
+		if err := sink.write(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+	entry.Buffer = buffer
+
+	if handler != nil {
+		return nil, handleEntry(handler, entry)
+	}
+	if formatter != nil && len(sinks) == 0 {
+		return formatter.Format(entry)
+	}
+	return nil, nil
+}
+
+// updatePipeline updates the pipeline of the logrus.Logger behind the FieldLogger. The pipeline of a
+// logrus.FieldLogger supplied to NewLogger is created on the first update, and removed if it becomes empty.
+func updatePipeline(log logrus.FieldLogger, update func(p *pipeline)) {
+	base := baseLogger(log)
+	if base == nil {
+		return
+	}
+	p, ok := base.Formatter.(*pipeline)
+	if !ok {
+		p = &pipeline{base: base, formatter: base.Formatter}
+	}
+	p.mu.Lock()
+	update(p)
+	empty := p.empty()
+	p.mu.Unlock()
+	if empty {
+		base.SetFormatter(p.formatter)
+		return
+	}
+	if !ok {
+		base.SetFormatter(p)
+	}
+}
+
+// coreHandler is the slog.Handler of a Logger backed by a slog.Handler, which passes the records
+// to its pipeline with the attributes as fields. The groups are prefixes of the field names.
+type coreHandler struct {
+	core   *pipeline
+	fields logrus.Fields
+	prefix string
+}
+
+// Enabled implements the slog.Handler interface, the level is enabled if the logrus.Logger enables it
+// (the LevelController sets its level to the most verbose level of the components).
+func (h *coreHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.base.IsLevelEnabled(logrusLevel(level))
+}
+
+// Handle implements the slog.Handler interface.
+func (h *coreHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := h.withAttrs(nil)
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.prefix, a)
+		return true
+	})
+	entry := &logrus.Entry{
+		Logger:  h.core.base,
+		Data:    fields,
+		Time:    record.Time,
+		Level:   logrusLevel(record.Level),
+		Message: record.Message,
+		Context: ctx,
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.Caller = &frame
+	}
+	_, err := h.core.write(entry, true)
+	return err
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *coreHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &coreHandler{core: h.core, fields: h.withAttrs(attrs), prefix: h.prefix}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *coreHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &coreHandler{core: h.core, fields: h.withAttrs(nil), prefix: h.prefix + name + "."}
+}
+
+// withAttrs returns a copy of the fields of the handler with the attributes.
+func (h *coreHandler) withAttrs(attrs []slog.Attr) logrus.Fields {
+	return withAttrs(h.fields, h.prefix, attrs)
+}
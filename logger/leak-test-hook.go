@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// LeakHook is a logrus.Hook for tests, which records the log entries containing any of the sensitive values.
+type LeakHook struct {
+	mu        sync.Mutex
+	sensitive []string
+	leaks     []string
+}
+
+// NewLeakTestHook adds a LeakHook to the logrus.Logger of the Logger, which fails the test at its end
+// if any of the sensitive values was emitted in a message or a field. The records of the slog API of a Logger
+// backed by a slog.Handler do not reach the hooks, so they are not checked.
+func NewLeakTestHook(t *testing.T, l *Logger, sensitive ...string) *LeakHook {
+	assert := require.New(t)
+	assert.NotNil(baseLogger(l.log), "Logger should be backed by a logrus.Logger")
+
+	hook := &LeakHook{sensitive: sensitive}
+	l.AddHook(hook)
+	t.Cleanup(func() {
+		assert.Empty(hook.Leaks(), "No sensitive value should have been emitted")
+	})
+	return hook
+}
+
+// Levels implements the logrus.Hook interface, the LeakHook checks the entries of all levels
+func (h *LeakHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus.Hook interface, it records the message and fields containing sensitive values.
+func (h *LeakHook) Fire(entry *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, sensitive := range h.sensitive {
+		if strings.Contains(entry.Message, sensitive) {
+			h.leaks = append(h.leaks, fmt.Sprintf("message %q contains %q", entry.Message, sensitive))
+		}
+		for key, value := range entry.Data {
+			if strings.Contains(fmt.Sprint(value), sensitive) {
+				h.leaks = append(h.leaks, fmt.Sprintf("field %s of %q contains %q", key, entry.Message, sensitive))
+			}
+		}
+	}
+	return nil
+}
+
+// Leaks returns the descriptions of the recorded leaks.
+func (h *LeakHook) Leaks() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string{}, h.leaks...)
+}
+
+// Reset forgets the recorded leaks.
+func (h *LeakHook) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.leaks = nil
+}
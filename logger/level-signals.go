@@ -0,0 +1,32 @@
+//go:build !windows
+
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// HandleSignals raises the level of the logger on SIGUSR1 and lowers it on SIGUSR2, until the context is cancelled.
+// If revertAfter is positive, the level is reset after it.
+func (lc *LevelController) HandleSignals(ctx context.Context, revertAfter time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			if sig == syscall.SIGUSR1 {
+				lc.Raise("", revertAfter)
+			} else {
+				lc.Lower("", revertAfter)
+			}
+		}
+	}
+}
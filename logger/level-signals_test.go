@@ -0,0 +1,36 @@
+//go:build !windows
+
+package logger
+
+import (
+	"context"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func (ls *LoggerSuite) TestLevelControllerSignals() {
+	l := logrus.New()
+	l.SetLevel(logrus.InfoLevel)
+	levels, err := NewLevelController(NewLogger(l, nil))
+	ls.NoError(err, "Level controller should have been created")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		levels.HandleSignals(ctx, 0)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	ls.Eventually(func() bool {
+		ls.NoError(syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+		return levels.Level("") > logrus.InfoLevel
+	}, time.Second, 20*time.Millisecond, "The level should have been raised")
+}
@@ -0,0 +1,12 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// HandleSignals waits until the context is cancelled, as there are no SIGUSR1 and SIGUSR2 signals on Windows.
+// Use the HTTP handler of the LevelController to change the level instead.
+func (lc *LevelController) HandleSignals(ctx context.Context, revertAfter time.Duration) {
+	<-ctx.Done()
+}
@@ -0,0 +1,286 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	models "github.com/toolboxmodels"
+)
+
+// LevelController changes the log level of the logrus.Logger behind a Logger (and the levels of its components
+// created by NewComponentLogger) at runtime, over HTTP and by signals, with an optional automatic revert.
+type LevelController struct {
+	mu         sync.Mutex
+	base       *logrus.Logger
+	initial    logrus.Level
+	level      logrus.Level
+	components map[string]logrus.Level
+	timers     map[string]*time.Timer
+}
+
+// NewLevelController creates a LevelController for the logrus.Logger of the Logger (shared with its component
+// and context loggers). The current level of the logrus.Logger is restored by the reverts.
+// The hooks of the logrus.Logger (and the hooks added by Logger.AddHook) only receive the enabled entries.
+func NewLevelController(l *Logger) (*LevelController, error) {
+	base := baseLogger(l.log)
+	if base == nil {
+		return nil, errors.New("Failed to create level controller: logger is not backed by a logrus.Logger")
+	}
+	lc := &LevelController{
+		base:       base,
+		initial:    base.GetLevel(),
+		level:      base.GetLevel(),
+		components: map[string]logrus.Level{},
+		timers:     map[string]*time.Timer{},
+	}
+	l.levels = lc
+	updatePipeline(l.log, func(p *pipeline) {
+		p.levels = lc
+	})
+	hooks := logrus.LevelHooks{}
+	for level, levelHooks := range base.Hooks {
+		for _, hook := range levelHooks {
+			hooks[level] = append(hooks[level], lc.filterHook(hook))
+		}
+	}
+	base.ReplaceHooks(hooks)
+	return lc, nil
+}
+
+// AddHook adds the hook to the logrus.Logger of the Logger. If the Logger has a LevelController, the hook only
+// receives the entries enabled by the levels of their components.
+func (l *Logger) AddHook(hook logrus.Hook) {
+	base := baseLogger(l.log)
+	if base == nil {
+		return
+	}
+	if l.levels != nil {
+		hook = l.levels.filterHook(hook)
+	}
+	base.AddHook(hook)
+}
+
+// LevelController returns the LevelController of the Logger (created by NewLevelController
+// or by NewCommonLoggerFromConfiguration), or nil.
+func (l *Logger) LevelController() *LevelController {
+	return l.levels
+}
+
+// Level returns the level of the component, or the level of the logger for an empty component.
+func (lc *LevelController) Level(component string) logrus.Level {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if level, ok := lc.components[component]; ok && component != "" {
+		return level
+	}
+	return lc.level
+}
+
+// SetLevel sets the level of the component, or the level of the logger for an empty component.
+// If revertAfter is positive, the level is reset after it.
+func (lc *LevelController) SetLevel(component string, level logrus.Level, revertAfter time.Duration) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.stopTimer(component)
+	if component == "" {
+		lc.level = level
+	} else {
+		lc.components[component] = level
+	}
+	lc.apply()
+
+	if revertAfter > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(revertAfter, func() {
+			lc.mu.Lock()
+			defer lc.mu.Unlock()
+			// the level was changed again since the timer was started
+			if lc.timers[component] != timer {
+				return
+			}
+			lc.reset(component)
+		})
+		lc.timers[component] = timer
+	}
+}
+
+// ResetLevel resets the level of the logger to its initial level for an empty component,
+// or removes the level of the component.
+func (lc *LevelController) ResetLevel(component string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.reset(component)
+}
+
+// Raise makes the component (or the logger for an empty component) one level more verbose (up to trace).
+func (lc *LevelController) Raise(component string, revertAfter time.Duration) {
+	level := lc.Level(component)
+	if level < logrus.TraceLevel {
+		level++
+	}
+	lc.SetLevel(component, level, revertAfter)
+}
+
+// Lower makes the component (or the logger for an empty component) one level less verbose (down to error).
+func (lc *LevelController) Lower(component string, revertAfter time.Duration) {
+	level := lc.Level(component)
+	if level > logrus.ErrorLevel {
+		level--
+	}
+	lc.SetLevel(component, level, revertAfter)
+}
+
+// reset resets the level of the component, the caller must hold the lock.
+func (lc *LevelController) reset(component string) {
+	lc.stopTimer(component)
+	if component == "" {
+		lc.level = lc.initial
+	} else {
+		delete(lc.components, component)
+	}
+	lc.apply()
+}
+
+// stopTimer stops the revert timer of the component, the caller must hold the lock.
+func (lc *LevelController) stopTimer(component string) {
+	if timer, ok := lc.timers[component]; ok {
+		timer.Stop()
+		delete(lc.timers, component)
+	}
+}
+
+// apply sets the most verbose of the levels on the logrus.Logger, the caller must hold the lock.
+// The entries of the less verbose components are dropped by enabled.
+func (lc *LevelController) apply() {
+	level := lc.level
+	for _, componentLevel := range lc.components {
+		if componentLevel > level {
+			level = componentLevel
+		}
+	}
+	lc.base.SetLevel(level)
+}
+
+// enabled reports if the entry is enabled by the level of its component, or by the level of the logger.
+func (lc *LevelController) enabled(entry *logrus.Entry) bool {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	level := lc.level
+	if component, ok := entry.Data["component"].(string); ok {
+		if componentLevel, ok := lc.components[component]; ok {
+			level = componentLevel
+		}
+	}
+	return entry.Level <= level
+}
+
+// levelHook is a logrus.Hook, which fires the wrapped hook only for the entries enabled by the LevelController.
+// The logrus.Logger fires the hooks before the formatter, so the hooks would receive the dropped entries too.
+type levelHook struct {
+	logrus.Hook
+	levels *LevelController
+}
+
+// Fire implements the logrus.Hook interface, it fires the wrapped hook if the entry is enabled.
+func (h *levelHook) Fire(entry *logrus.Entry) error {
+	if !h.levels.enabled(entry) {
+		return nil
+	}
+	return h.Hook.Fire(entry)
+}
+
+// filterHook wraps the hook with the level filter of the LevelController, unless it is wrapped already.
+func (lc *LevelController) filterHook(hook logrus.Hook) logrus.Hook {
+	if filtered, ok := hook.(*levelHook); ok && filtered.levels == lc {
+		return hook
+	}
+	return &levelHook{Hook: unwrapHook(hook), levels: lc}
+}
+
+// unwrapHook returns the hook wrapped by the level filter, or the hook.
+func unwrapHook(hook logrus.Hook) logrus.Hook {
+	if filtered, ok := hook.(*levelHook); ok {
+		return filtered.Hook
+	}
+	return hook
+}
+
+// levelState is the JSON representation of the levels served by the LevelController.
+type levelState struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// levelRequest is the JSON body of the level change requests.
+type levelRequest struct {
+	Component   string `json:"component"`
+	Level       string `json:"level"`
+	RevertAfter string `json:"revert_after"`
+}
+
+// ServeHTTP implements the http.Handler interface.
+// GET returns the levels, PUT and POST change a level with a {"component": "", "level": "debug",
+// "revert_after": "10m"} body, and DELETE resets the level of the component in the component query parameter.
+func (lc *LevelController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		req := levelRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		level, err := logrus.ParseLevel(req.Level)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid level "+req.Level)
+			return
+		}
+		revertAfter := time.Duration(0)
+		if req.RevertAfter != "" {
+			if revertAfter, err = time.ParseDuration(req.RevertAfter); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid revert_after "+req.RevertAfter)
+				return
+			}
+		}
+		lc.SetLevel(req.Component, level, revertAfter)
+	case http.MethodDelete:
+		lc.ResetLevel(r.URL.Query().Get("component"))
+	default:
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	lc.mu.Lock()
+	state := levelState{Level: lc.level.String(), Components: map[string]string{}}
+	for component, level := range lc.components {
+		state.Components[component] = level.String()
+	}
+	lc.mu.Unlock()
+	respondJSON(w, http.StatusOK, state)
+}
+
+// Mount registers the LevelController on the path of the gorilla/mux router.
+func (lc *LevelController) Mount(router *mux.Router, path string) {
+	router.Handle(path, lc).Methods(http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete)
+}
+
+// respondJSON writes the JSON response.
+func respondJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.WithError(err).Error("Failed to write HTTP response")
+	}
+}
+
+// respondError writes the error response in the models.ErrorResponse format.
+func respondError(w http.ResponseWriter, code int, message string) {
+	respondJSON(w, code, models.ErrorResponse{
+		Error: models.ErrorResponseFormat{Code: code, Message: message},
+	})
+}
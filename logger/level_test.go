@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func (ls *LoggerSuite) TestLevelController() {
+	out := &bytes.Buffer{}
+	l := logrus.New()
+	l.SetOutput(out)
+	l.SetLevel(logrus.InfoLevel)
+	testLogger := NewLogger(l, nil)
+	gormComponent := testLogger.NewComponentLogger("GORM")
+
+	levels, err := NewLevelController(testLogger)
+	ls.NoError(err, "Level controller should have been created")
+	ls.Equal(levels, testLogger.NewComponentLogger("other").LevelController(), "Child loggers should share the controller")
+
+	levels.SetLevel("GORM", logrus.DebugLevel, 0)
+	gormComponent.Entry().Debug("GORM debug")
+	testLogger.Entry().Debug("Logger debug")
+	ls.Contains(out.String(), "GORM debug", "The debug entries of the component should have been logged")
+	ls.NotContains(out.String(), "Logger debug", "The debug entries of the logger should have been dropped")
+
+	levels.Raise("", 0)
+	ls.Equal(logrus.DebugLevel, l.GetLevel())
+	levels.Lower("", 20*time.Millisecond)
+	ls.Equal(logrus.InfoLevel, levels.Level(""))
+	levels.SetLevel("", logrus.ErrorLevel, 20*time.Millisecond)
+	ls.Eventually(func() bool { return levels.Level("") == logrus.InfoLevel }, time.Second, 5*time.Millisecond,
+		"The level should have been reverted")
+
+	levels.ResetLevel("GORM")
+	ls.Equal(logrus.InfoLevel, levels.Level("GORM"))
+	ls.Equal(logrus.InfoLevel, l.GetLevel())
+}
+
+func (ls *LoggerSuite) TestLevelControllerHooks() {
+	l := logrus.New()
+	l.SetOutput(&bytes.Buffer{})
+	l.SetLevel(logrus.InfoLevel)
+	before := logrusTest.NewLocal(l)
+	testLogger := NewLogger(l, nil)
+	testLogger.SetRedactor(NewDefaultRedactor())
+
+	levels, err := NewLevelController(testLogger)
+	ls.NoError(err, "Level controller should have been created")
+	after := &logrusTest.Hook{}
+	testLogger.AddHook(after)
+	leaks := NewLeakTestHook(ls.T(), testLogger, "hunter2")
+
+	levels.SetLevel("GORM", logrus.DebugLevel, 0)
+	testLogger.NewComponentLogger("GORM").Entry().Debug("GORM debug")
+	testLogger.WithField("password", "hunter2").Debug("Logger debug")
+	for _, hook := range []*logrusTest.Hook{before, after} {
+		ls.Len(hook.AllEntries(), 1, "The hooks should not receive the dropped entries")
+		ls.Equal("GORM debug", hook.LastEntry().Message)
+	}
+	ls.Empty(leaks.Leaks())
+
+	testLogger.SetRedactor(nil)
+	testLogger.NewComponentLogger("GORM").WithField("password", "hunter2").Debug("GORM debug")
+	ls.Len(leaks.Leaks(), 1, "The hooks should receive the enabled entries")
+	leaks.Reset()
+}
+
+func (ls *LoggerSuite) TestLevelControllerHTTP() {
+	l := logrus.New()
+	l.SetLevel(logrus.InfoLevel)
+	levels, err := NewLevelController(NewLogger(l, nil))
+	ls.NoError(err, "Level controller should have been created")
+	router := mux.NewRouter()
+	levels.Mount(router, "/log/level")
+
+	serve := func(method, url, body string) (int, map[string]interface{}) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		router.ServeHTTP(rr, req)
+		resp := map[string]interface{}{}
+		ls.NoError(json.Unmarshal(rr.Body.Bytes(), &resp), "Response should be JSON")
+		return rr.Code, resp
+	}
+
+	code, resp := serve(http.MethodPut, "/log/level", `{"component": "GORM", "level": "debug", "revert_after": "1m"}`)
+	ls.Equal(http.StatusOK, code)
+	ls.Equal(map[string]interface{}{"level": "info", "components": map[string]interface{}{"GORM": "debug"}}, resp)
+
+	code, _ = serve(http.MethodPut, "/log/level", `{"level": "loud"}`)
+	ls.Equal(http.StatusBadRequest, code, "Invalid levels should be rejected")
+
+	code, resp = serve(http.MethodDelete, "/log/level?component=GORM", "")
+	ls.Equal(http.StatusOK, code)
+	ls.Equal(map[string]interface{}{}, resp["components"])
+}
@@ -2,10 +2,14 @@
 // Use the NewCommonLogger constructor to create your application's logger
 // Use the NewComponentLogger method to create child loggers for components of your application
 // Use Entry WithField WithFields and WithError to create new log entries
+// Use WithContext and FromContext to pass request scoped loggers in contexts
+// The common loggers are backed by a log/slog Handler, the logrus entries are adapted to slog records,
+// use Slog to log with the slog API
 package logger
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"regexp"
 	"strconv"
@@ -36,10 +40,16 @@ var inBrackets = regexp.MustCompile(`\[([^]]+)\]`)
 
 // Logger is a wrapper around Logrus FieldLogger with default fields
 type Logger struct {
-	log           logrus.FieldLogger
-	defaultFields logrus.Fields
-	formatErrors  bool
-	gormConf      *gormLog.Config
+	log              logrus.FieldLogger
+	defaultFields    logrus.Fields
+	formatErrors     bool
+	gormConf         *gormLog.Config
+	sampler          *Sampler
+	redactor         *Redactor
+	levels           *LevelController
+	sinks            []*Sink
+	core             *pipeline
+	redactParameters bool
 }
 
 // NewLogger creates a new logger instance with the supplied Logrus FieldLogger and default fields
@@ -106,12 +116,14 @@ type configGetter interface {
 }
 
 // NewCommonLoggerFromConfiguration is the prefferred way to create the Common Logger
+// The entries are written to stdout by a slog.JSONHandler (or a slog.TextHandler in Development Mode),
+// or to the sinks configured by APP_LOG_SINKS
 func NewCommonLoggerFromConfiguration(serviceName, serviceVersion string, config configGetter) *Logger {
 	log := logrus.New()
-	log.SetOutput(os.Stdout)
 
 	ok, _ := strconv.ParseBool(config.Get(constants.APP_DEBUG))
 	log.SetReportCaller(ok)
+	handlerOptions := HandlerOptions(ok)
 
 	level, err := logrus.ParseLevel(config.Get(constants.APP_LOG_LEVEL))
 	if err != nil {
@@ -119,11 +131,6 @@ func NewCommonLoggerFromConfiguration(serviceName, serviceVersion string, config
 	}
 	log.SetLevel(level)
 
-	log.SetFormatter(BasicJSONFormatter)
-	if ok, _ := strconv.ParseBool(config.Get(constants.APP_LOG_DEV)); ok {
-		log.SetFormatter(BasicTextFormatter)
-	}
-
 	commonLog := NewLogger(log, logrus.Fields{
 		"service": serviceName,
 		"version": serviceVersion,
@@ -131,6 +138,31 @@ func NewCommonLoggerFromConfiguration(serviceName, serviceVersion string, config
 		"host":    config.Hostname(),
 	})
 
+	// the sinks replace the stdout handler, stdout is one of the sinks if it is configured
+	sinks, sinksErr := sinksFromConfiguration(serviceName, config)
+	if sinks != nil {
+		commonLog.core = newPipeline(log, nil)
+		commonLog.SetSinks(sinks...)
+	} else {
+		var handler slog.Handler = slog.NewJSONHandler(os.Stdout, handlerOptions)
+		if ok, _ := strconv.ParseBool(config.Get(constants.APP_LOG_DEV)); ok {
+			handler = slog.NewTextHandler(os.Stdout, handlerOptions)
+		}
+		commonLog.core = newPipeline(log, handler)
+	}
+
+	// the logger is a logrus.Logger, so the level controller cannot fail
+	_, _ = NewLevelController(commonLog)
+
+	if sampler := samplerFromConfiguration(config); sampler != nil {
+		commonLog.SetSampler(sampler)
+	}
+
+	if sinksErr != nil {
+		commonLog.WithError(sinksErr).Error("Failed to create log sinks, logging to stdout")
+	}
+
+	commonLog.SetGormOptions(gormOptionsFromConfiguration(commonLog, config))
 	switch level {
 	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
 		commonLog.gormConf.LogLevel = gormLog.Error
@@ -144,15 +176,7 @@ func NewCommonLoggerFromConfiguration(serviceName, serviceVersion string, config
 // NewComponentLogger creates a new logger with the loggers default FieldLogger and fields
 // and adds a new field 'component' with the supplied componentName.
 func (l *Logger) NewComponentLogger(componentName string) *Logger {
-	newFields := logrus.Fields{}
-	for key, value := range l.defaultFields {
-		newFields[key] = value
-	}
-	newFields["component"] = componentName
-	newLogger := NewLogger(l.log, newFields)
-	newLogger.gormConf.SlowThreshold = l.gormConf.SlowThreshold
-	newLogger.gormConf.LogLevel = l.gormConf.LogLevel
-	return newLogger
+	return l.child(logrus.Fields{"component": componentName})
 }
 
 // Entry creates a new log entry with the default fields
@@ -171,9 +195,15 @@ func (l *Logger) WithFields(fields logrus.Fields) *logrus.Entry {
 	return l.log.WithFields(l.defaultFields).WithFields(fields)
 }
 
-// WithError adds a new field with key "error" and value is the parsed version of the supplied error object
+// WithError adds a new field with key "error" and value is the parsed version of the supplied error object.
+// The structured fields of the error (error.message, error.type, error.stack, error.causes and error.messageCode)
+// are added too, unless the error is nil.
 func (l *Logger) WithError(err error) *logrus.Entry {
-	return l.log.WithFields(l.defaultFields).WithField("error", l.parseError(err))
+	entry := l.log.WithFields(l.defaultFields).WithField("error", l.parseError(err))
+	if err == nil {
+		return entry
+	}
+	return entry.WithFields(errorFields(err))
 }
 
 // parseError tries to unwrap the underlying pkg/errors.Error, and return it as a string.
@@ -214,19 +244,32 @@ func getLogLevelFromGormMsg(msg string) string {
 }
 
 // Printf implements the gorm/logger.Writer interface
+// If a Redactor is set, the string literals of the SQL statements are redacted
 func (l *Logger) Printf(format string, args ...interface{}) {
-	switch getLogLevelFromGormMsg(format) {
+	level := getLogLevelFromGormMsg(format)
+	msg := fmt.Sprintf(strings.Replace(format, "["+level+"] ", "", 1), args...)
+	if l.redactor != nil {
+		msg = l.redactor.redactSQL(msg)
+	}
+	switch level {
 	case constants.LOG_LEVEL_ERROR:
-		l.Entry().Errorf(strings.Replace(format, "[error] ", "", 1), args...)
+		l.Entry().Error(msg)
 	case constants.LOG_LEVEL_WARN:
-		l.Entry().Warnf(strings.Replace(format, "[warn] ", "", 1), args...)
+		l.Entry().Warn(msg)
 	default:
-		l.Entry().Infof(strings.Replace(format, "[info] ", "", 1), args...)
+		l.Entry().Info(msg)
 	}
 }
 
 // NewGormLogger creates a gorm/logger.Interface from the CommonLogger
+// The SQL statements are logged with the sql, rows, duration_ms, caller and error fields by the logger attached to
+// the context of the statement (see WithContext), or by the component logger.
+// The entries have the trace_id and span_id fields, if the context of the statement carries an OpenTelemetry span
 func (l *Logger) NewGormLogger(componentName string) gormLog.Interface {
-	gormLogger := l.NewComponentLogger(componentName)
-	return gormLog.New(gormLogger, *gormLogger.gormConf)
+	return &gormLogger{
+		log:              l.NewComponentLogger(componentName),
+		component:        componentName,
+		conf:             *l.gormConf,
+		redactParameters: l.redactParameters,
+	}
 }
@@ -0,0 +1,272 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces the redacted values in the log entries.
+const RedactedValue = "******"
+
+// Patterns of the sensitive values, which can be redacted anywhere in the messages and the string fields.
+var (
+	// JWTPattern matches JSON Web Tokens.
+	JWTPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+	// CardNumberPattern matches payment card numbers (13-19 digits, optionally grouped by spaces or dashes).
+	// The Redactor only masks the matches passing the Luhn check, so the other long numbers are kept.
+	CardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// sqlLiteralPattern matches the quoted string literals of SQL statements.
+var sqlLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// maxRedactionDepth limits the recursion of the Redactor into the nested values (and the cycles of pointers).
+const maxRedactionDepth = 8
+
+// DefaultRedactedFields are the field names redacted by default.
+var DefaultRedactedFields = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"authorization",
+	"api_key",
+}
+
+// RedactorOptions are the possible options for the NewRedactor to create a Redactor.
+type RedactorOptions struct {
+	// Fields are the names of the fields whose values are redacted (case insensitive).
+	Fields []string
+
+	// Patterns are the regular expressions of the sensitive values redacted in the messages and the string fields.
+	Patterns []*regexp.Regexp
+
+	// SQLLiterals enables the redaction of the string literals of the SQL statements logged by the gorm logger.
+	SQLLiterals bool
+}
+
+// Redactor masks the sensitive values of the log entries before they reach the formatter.
+type Redactor struct {
+	fields      map[string]bool
+	patterns    []*regexp.Regexp
+	sqlLiterals bool
+}
+
+// NewRedactor creates a new Redactor with the supplied RedactorOptions
+func NewRedactor(opts RedactorOptions) *Redactor {
+	fields := make(map[string]bool, len(opts.Fields))
+	for _, field := range opts.Fields {
+		fields[strings.ToLower(field)] = true
+	}
+	return &Redactor{
+		fields:      fields,
+		patterns:    opts.Patterns,
+		sqlLiterals: opts.SQLLiterals,
+	}
+}
+
+// NewDefaultRedactor creates a new Redactor for the DefaultRedactedFields, JWTs, card numbers, emails and SQL literals.
+func NewDefaultRedactor() *Redactor {
+	return NewRedactor(RedactorOptions{
+		Fields:      DefaultRedactedFields,
+		Patterns:    []*regexp.Regexp{JWTPattern, CardNumberPattern, EmailPattern},
+		SQLLiterals: true,
+	})
+}
+
+// RedactString masks the matches of the patterns in the string.
+func (r *Redactor) RedactString(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if pattern == CardNumberPattern && !luhnValid(match) {
+				return match
+			}
+			return RedactedValue
+		})
+	}
+	return s
+}
+
+// luhnValid reports if the digits of the card number (ignoring the spaces and dashes) pass the Luhn check.
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			continue
+		}
+		digit := int(number[i] - '0')
+		if double {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// redactSQL masks the string literals of the SQL statement if it is enabled, then the matches of the patterns.
+func (r *Redactor) redactSQL(s string) string {
+	if r.sqlLiterals {
+		s = sqlLiteralPattern.ReplaceAllString(s, "'"+RedactedValue+"'")
+	}
+	return r.RedactString(s)
+}
+
+// redactValue masks the string values of the fields, and the strings and the redacted field names nested in
+// errors, maps, structs, slices and pointers. The values are only replaced if something was redacted.
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	if redacted, changed := r.redactNested(value, 0); changed {
+		return redacted
+	}
+	return value
+}
+
+// redactNested returns the redacted copy of the value, and reports if anything was redacted. An error is replaced
+// by its redacted message, the maps and the structs are copied into map[string]interface{} (the exported
+// struct fields by their json names), the slices and arrays into []interface{}. Unexported fields are not visited.
+func (r *Redactor) redactNested(value interface{}, depth int) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		redacted := r.RedactString(v)
+		return redacted, redacted != v
+	case []string:
+		redacted, changed := make([]string, len(v)), false
+		for i, s := range v {
+			redacted[i] = r.RedactString(s)
+			changed = changed || redacted[i] != s
+		}
+		return redacted, changed
+	case error:
+		redacted := r.RedactString(v.Error())
+		return redacted, redacted != v.Error()
+	}
+	if depth >= maxRedactionDepth {
+		return value, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return value, false
+		}
+		return r.redactNested(rv.Elem().Interface(), depth+1)
+	case reflect.Map:
+		redacted, changed := make(map[string]interface{}, rv.Len()), false
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			changed = r.redactEntry(redacted, key, iter.Value().Interface(), depth) || changed
+		}
+		return redacted, changed
+	case reflect.Struct:
+		redacted, changed := map[string]interface{}{}, false
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if field.PkgPath != "" || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			changed = r.redactEntry(redacted, name, rv.Field(i).Interface(), depth) || changed
+		}
+		return redacted, changed
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return value, false
+		}
+		redacted, changed := make([]interface{}, rv.Len()), false
+		for i := 0; i < rv.Len(); i++ {
+			var itemChanged bool
+			redacted[i], itemChanged = r.redactNested(rv.Index(i).Interface(), depth+1)
+			changed = changed || itemChanged
+		}
+		return redacted, changed
+	}
+	return value, false
+}
+
+// redactEntry sets the redacted value of the named map entry or struct field, and reports if it was redacted.
+// The (non-zero) values of the redacted field names are masked.
+func (r *Redactor) redactEntry(redacted map[string]interface{}, name string, value interface{}, depth int) bool {
+	if r.fields[strings.ToLower(name)] && value != nil && !reflect.ValueOf(value).IsZero() {
+		redacted[name] = RedactedValue
+		return true
+	}
+	var changed bool
+	redacted[name], changed = r.redactNested(value, depth+1)
+	return changed
+}
+
+// Levels implements the logrus.Hook interface, the Redactor redacts the entries of all levels
+func (r *Redactor) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus.Hook interface, it redacts the message and the fields of the entry.
+// The fields are replaced by a redacted copy, so the Entry of the caller is not modified.
+func (r *Redactor) Fire(entry *logrus.Entry) error {
+	entry.Message = r.RedactString(entry.Message)
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		if r.fields[strings.ToLower(key)] {
+			data[key] = RedactedValue
+			continue
+		}
+		data[key] = r.redactValue(value)
+	}
+	entry.Data = data
+	return nil
+}
+
+// SetRedactor enables the redaction of the entries written by the logrus.Logger of the Logger (shared with its
+// component and context loggers). The Redactor is fired before the other hooks of the logrus.Logger, so the hooks
+// receive the redacted entries too. The records of the slog API of a Logger backed by a slog.Handler are redacted
+// by the Logger before the slog.Handler. The SQL literals are redacted by the gorm loggers created after SetRedactor.
+func (l *Logger) SetRedactor(redactor *Redactor) {
+	l.redactor = redactor
+	if l.core != nil {
+		l.core.mu.Lock()
+		l.core.redactor = redactor
+		l.core.mu.Unlock()
+	}
+	base := baseLogger(l.log)
+	if base == nil {
+		return
+	}
+
+	hooks := logrus.LevelHooks{}
+	for level, levelHooks := range base.Hooks {
+		for _, hook := range levelHooks {
+			if _, ok := unwrapHook(hook).(*Redactor); !ok {
+				hooks[level] = append(hooks[level], hook)
+			}
+		}
+	}
+	if redactor != nil {
+		var hook logrus.Hook = redactor
+		if l.levels != nil {
+			hook = l.levels.filterHook(hook)
+		}
+		for _, level := range redactor.Levels() {
+			hooks[level] = append([]logrus.Hook{hook}, hooks[level]...)
+		}
+	}
+	base.ReplaceHooks(hooks)
+}
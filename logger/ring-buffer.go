@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// RingBuffer is an io.Writer keeping the last written entries in memory, to serve them over HTTP.
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates a new RingBuffer keeping the last size entries.
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = 1
+	}
+	return &RingBuffer{entries: make([][]byte, size)}
+}
+
+// Write implements the io.Writer interface, every write is an entry.
+func (rb *RingBuffer) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.entries[rb.next] = entry
+	rb.next = (rb.next + 1) % len(rb.entries)
+	if rb.next == 0 {
+		rb.full = true
+	}
+	return len(p), nil
+}
+
+// Entries returns the last limit entries from the oldest to the newest, or all of them for a non positive limit.
+func (rb *RingBuffer) Entries(limit int) [][]byte {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	entries := append([][]byte{}, rb.entries[:rb.next]...)
+	if rb.full {
+		entries = append(append([][]byte{}, rb.entries[rb.next:]...), entries...)
+	}
+	if limit > 0 && limit < len(entries) {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries
+}
+
+// ServeHTTP implements the http.Handler interface, it writes the entries as they were formatted,
+// the limit query parameter limits the number of the newest entries.
+func (rb *RingBuffer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid limit "+value)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	for _, entry := range rb.Entries(limit) {
+		if _, err := w.Write(entry); err != nil {
+			return
+		}
+	}
+}
+
+// Mount registers the RingBuffer on the path of the gorilla/mux router.
+func (rb *RingBuffer) Mount(router *mux.Router, path string) {
+	router.Handle(path, rb).Methods(http.MethodGet)
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// RotatingFile is an io.WriteCloser appending to a file, which is rotated when it reaches its maximum size.
+// The rotated files are renamed to path.1, path.2, ... and only the newest MaxBackups are kept.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFile opens (or creates) the file at the path for appending. The file is rotated when
+// a write would exceed the maxSize bytes, a non positive maxSize disables the rotation.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if path == "" {
+		return nil, errors.New("Failed to open log file: the path is empty")
+	}
+	rf := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write implements the io.Writer interface. If the rotation fails, the data is still appended to the file
+// (reopened at its path) and the error of the rotation is returned.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return 0, errors.Errorf("Failed to write log file %s: the file is closed", rf.path)
+	}
+	var rotateErr error
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if rotateErr = rf.rotate(); rf.file == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	if err != nil {
+		return n, errors.Wrapf(err, "Failed to write log file %s", rf.path)
+	}
+	return n, rotateErr
+}
+
+// Close implements the io.Closer interface.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return errors.Wrapf(err, "Failed to close log file %s", rf.path)
+}
+
+// open opens the file for appending, the caller must hold the lock.
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open log file %s", rf.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrapf(err, "Failed to open log file %s", rf.path)
+	}
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// rotate renames the file and the backups, removes the oldest backup and opens a new file,
+// the caller must hold the lock. The file is reopened at its path even if the renames fail,
+// so the writes can go on, the file is only nil if it cannot be opened.
+func (rf *RotatingFile) rotate() error {
+	err := rf.file.Close()
+	rf.file = nil
+	if err == nil {
+		err = rf.shift()
+	}
+	if openErr := rf.open(); openErr != nil {
+		return openErr
+	}
+	return errors.Wrapf(err, "Failed to rotate log file %s", rf.path)
+}
+
+// shift removes the file without backups, or renames the file and the backups to the next backups
+// (overwriting the oldest one).
+func (rf *RotatingFile) shift() error {
+	if rf.maxBackups <= 0 {
+		if err := os.Remove(rf.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	for i := rf.maxBackups; i > 0; i-- {
+		if err := os.Rename(rf.backup(i-1), rf.backup(i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// backup returns the path of the i-th backup, or the path of the file for 0.
+func (rf *RotatingFile) backup(i int) string {
+	if i == 0 {
+		return rf.path
+	}
+	return fmt.Sprintf("%s.%d", rf.path, i)
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	constants "github.com/toolboxconstants"
+)
+
+// SamplingPolicy configures the sampling of the log entries with the same level and message:
+// the first Initial entries are logged in every second, then every Thereafter-th entry.
+// A non positive Initial disables the sampling, a non positive Thereafter drops every entry after the Initial ones.
+type SamplingPolicy struct {
+	Initial    int
+	Thereafter int
+}
+
+// samplingKey identifies the entries which are sampled together.
+type samplingKey struct {
+	level   logrus.Level
+	message string
+}
+
+// Sampler decides which log entries are logged according to the SamplingPolicy of their level,
+// and counts the dropped entries. The counters only hold the entries of the current second,
+// so messages with IDs or durations in them do not grow the Sampler.
+type Sampler struct {
+	// dropped is the first field to keep it 64-bit aligned for the atomic operations
+	dropped   uint64
+	mu        sync.Mutex
+	policy    SamplingPolicy
+	overrides map[logrus.Level]SamplingPolicy
+	second    int64
+	counters  map[samplingKey]int
+	now       func() time.Time
+}
+
+// NewSampler creates a new Sampler with the default SamplingPolicy and the per level overrides.
+func NewSampler(policy SamplingPolicy, overrides map[logrus.Level]SamplingPolicy) *Sampler {
+	return &Sampler{
+		policy:    policy,
+		overrides: overrides,
+		counters:  map[samplingKey]int{},
+		now:       time.Now,
+	}
+}
+
+// Sample reports if the entry should be logged, the dropped entries are counted.
+func (s *Sampler) Sample(entry *logrus.Entry) bool {
+	policy, ok := s.overrides[entry.Level]
+	if !ok {
+		policy = s.policy
+	}
+	if policy.Initial <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// the counters of the previous second are dropped together
+	if second := s.now().Unix(); second != s.second {
+		s.second = second
+		s.counters = map[samplingKey]int{}
+	}
+	key := samplingKey{level: entry.Level, message: entry.Message}
+	s.counters[key]++
+	count := s.counters[key]
+
+	if count <= policy.Initial ||
+		(policy.Thereafter > 0 && (count-policy.Initial)%policy.Thereafter == 0) {
+		return true
+	}
+	atomic.AddUint64(&s.dropped, 1)
+	return false
+}
+
+// Dropped returns the number of the dropped entries.
+func (s *Sampler) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// SetSampler enables the sampling of the entries written by the logrus.Logger of the Logger
+// (shared with its component and context loggers). A nil Sampler disables the sampling.
+// The entries are sampled when they are formatted, and logrus fires the hooks before that, so the hooks
+// of the logrus.Logger (like an error reporting hook) still receive the dropped entries too.
+func (l *Logger) SetSampler(sampler *Sampler) {
+	l.sampler = sampler
+	updatePipeline(l.log, func(p *pipeline) {
+		p.sampler = sampler
+	})
+}
+
+// Sampler returns the Sampler set by SetSampler (or by NewCommonLoggerFromConfiguration), or nil.
+func (l *Logger) Sampler() *Sampler {
+	return l.sampler
+}
+
+// samplerFromConfiguration creates the Sampler configured by the APP_LOG_SAMPLING_* variables,
+// or nil if the sampling is not configured. Invalid values are ignored.
+func samplerFromConfiguration(config configGetter) *Sampler {
+	initial, _ := strconv.Atoi(config.Get(constants.APP_LOG_SAMPLING_INITIAL))
+	thereafter, _ := strconv.Atoi(config.Get(constants.APP_LOG_SAMPLING_THEREAFTER))
+	overrides, err := ParseSamplingLevels(config.Get(constants.APP_LOG_SAMPLING_LEVELS))
+	if err != nil {
+		overrides = nil
+	}
+	if initial <= 0 && len(overrides) == 0 {
+		return nil
+	}
+	return NewSampler(SamplingPolicy{Initial: initial, Thereafter: thereafter}, overrides)
+}
+
+// ParseSamplingPolicy parses a SamplingPolicy in the "initial:thereafter" format, like "100:10".
+func ParseSamplingPolicy(policy string) (SamplingPolicy, error) {
+	parts := strings.SplitN(policy, ":", 2)
+	initial, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return SamplingPolicy{}, errors.Wrapf(err, "Invalid sampling policy %s", policy)
+	}
+	thereafter := 0
+	if len(parts) == 2 {
+		if thereafter, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return SamplingPolicy{}, errors.Wrapf(err, "Invalid sampling policy %s", policy)
+		}
+	}
+	return SamplingPolicy{Initial: initial, Thereafter: thereafter}, nil
+}
+
+// ParseSamplingLevels parses the per level SamplingPolicy overrides in the
+// "level=initial:thereafter,..." format, like "debug=10:100,error=0".
+func ParseSamplingLevels(levels string) (map[logrus.Level]SamplingPolicy, error) {
+	overrides := map[logrus.Level]SamplingPolicy{}
+	for _, pair := range strings.Split(levels, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("Invalid sampling level override %s", pair)
+		}
+		level, err := logrus.ParseLevel(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid sampling level override %s", pair)
+		}
+		policy, err := ParseSamplingPolicy(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		overrides[level] = policy
+	}
+	return overrides, nil
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	config "github.com/toolboxconfig"
+	constants "github.com/toolboxconstants"
+)
+
+func (ls *LoggerSuite) TestSampler() {
+	out := &bytes.Buffer{}
+	l := logrus.New()
+	l.SetOutput(out)
+	l.SetFormatter(BasicJSONFormatter)
+	testLogger := NewLogger(l, nil)
+
+	now := time.Unix(1600000000, 0)
+	sampler := NewSampler(SamplingPolicy{Initial: 2, Thereafter: 3}, map[logrus.Level]SamplingPolicy{
+		logrus.ErrorLevel: {},
+	})
+	sampler.now = func() time.Time { return now }
+	testLogger.SetSampler(sampler)
+	ls.Equal(sampler, testLogger.NewComponentLogger("test-component").Sampler(), "Child loggers should share the sampler")
+
+	for i := 0; i < 10; i++ {
+		testLogger.Entry().Info("Hot path")
+	}
+	testLogger.Entry().Info("Other message")
+	for i := 0; i < 5; i++ {
+		testLogger.Entry().Error("Hot path")
+	}
+	ls.Equal(4, strings.Count(out.String(), `"level":"info","msg":"Hot path"`),
+		"The first 2 and every 3rd info entries should have been logged")
+	ls.Equal(1, strings.Count(out.String(), "Other message"), "Other messages should be sampled separately")
+	ls.Equal(5, strings.Count(out.String(), `"level":"error"`), "Error entries should not be sampled")
+	ls.Equal(uint64(6), sampler.Dropped())
+
+	now = now.Add(time.Second)
+	out.Reset()
+	testLogger.Entry().Info("Hot path")
+	ls.Contains(out.String(), "Hot path", "The counters should be reset in every second")
+	ls.Len(sampler.counters, 1, "The counters of the previous second should have been dropped")
+
+	testLogger.SetSampler(nil)
+	ls.Equal(BasicJSONFormatter, l.Formatter, "The original formatter should have been restored")
+}
+
+func (ls *LoggerSuite) TestSamplerFromConfiguration() {
+	conf := config.NewConfig(map[string]*config.Variable{
+		constants.APP_LOG_SAMPLING_INITIAL:    {DefaultValue: "100"},
+		constants.APP_LOG_SAMPLING_THEREAFTER: {DefaultValue: "10"},
+		constants.APP_LOG_SAMPLING_LEVELS:     {DefaultValue: "debug=10:100, error=0"},
+	})
+	ls.NoError(conf.Setup(), "Configuration should have been set up")
+
+	commonLog := NewCommonLoggerFromConfiguration("test-service", "v1.2.3", conf)
+	ls.NotNil(commonLog.Sampler(), "Sampler should have been configured")
+	ls.Equal(SamplingPolicy{Initial: 100, Thereafter: 10}, commonLog.Sampler().policy)
+	ls.Equal(map[logrus.Level]SamplingPolicy{
+		logrus.DebugLevel: {Initial: 10, Thereafter: 100},
+		logrus.ErrorLevel: {},
+	}, commonLog.Sampler().overrides)
+
+	_, err := ParseSamplingLevels("verbose=1:2")
+	ls.Error(err, "Unknown levels should not be parsed")
+	_, err = ParseSamplingPolicy("a:b")
+	ls.Error(err, "Invalid policies should not be parsed")
+}
@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	constants "github.com/toolboxconstants"
+)
+
+// Names of the sinks configured by the APP_LOG_SINKS variable
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+	SinkMemory = "memory"
+)
+
+// Names of the formats of the sinks configured by the APP_LOG_SINKS variable
+const (
+	SinkFormatJSON = "json"
+	SinkFormatText = "text"
+)
+
+// LevelWriter is an io.Writer, which writes the entries with their level, like the syslog writer.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level logrus.Level, p []byte) (int, error)
+}
+
+// Sink is a destination of the log entries with its own level and formatter.
+type Sink struct {
+	// Name identifies the sink in the Logger.
+	Name string
+
+	// Level is the most verbose level written to the sink, the entries are filtered by the level
+	// of the logrus.Logger (and the LevelController) first.
+	Level logrus.Level
+
+	// Formatter formats the entries of the sink.
+	Formatter logrus.Formatter
+
+	// Writer receives the formatted entries, a LevelWriter receives their level too.
+	Writer io.Writer
+}
+
+// NewSink creates a new Sink writing the entries up to the level, formatted by the formatter, to the writer.
+func NewSink(name string, level logrus.Level, formatter logrus.Formatter, writer io.Writer) *Sink {
+	return &Sink{Name: name, Level: level, Formatter: formatter, Writer: writer}
+}
+
+// write formats and writes the entry, if its level is enabled.
+func (s *Sink) write(entry *logrus.Entry) error {
+	if entry.Level > s.Level {
+		return nil
+	}
+	serialized, err := s.Formatter.Format(entry)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to format log entry for sink %s", s.Name)
+	}
+	if lw, ok := s.Writer.(LevelWriter); ok {
+		_, err = lw.WriteLevel(entry.Level, serialized)
+	} else {
+		_, err = s.Writer.Write(serialized)
+	}
+	return errors.Wrapf(err, "Failed to write log entry to sink %s", s.Name)
+}
+
+// SetSinks replaces the output of the logrus.Logger of the Logger (shared with its component and context loggers)
+// with the sinks. The slog.Handler of a Logger backed by a slog.Handler receives the entries next to the sinks.
+// The LevelController and the Sampler filter the entries before they reach the sinks.
+func (l *Logger) SetSinks(sinks ...*Sink) {
+	l.sinks = sinks
+	updatePipeline(l.log, func(p *pipeline) {
+		p.sinks = sinks
+	})
+}
+
+// Sinks returns the sinks set by SetSinks (or by NewCommonLoggerFromConfiguration).
+func (l *Logger) Sinks() []*Sink {
+	return l.sinks
+}
+
+// Sink returns the sink with the name, or nil.
+func (l *Logger) Sink(name string) *Sink {
+	for _, sink := range l.sinks {
+		if sink.Name == name {
+			return sink
+		}
+	}
+	return nil
+}
+
+// RingBuffer returns the RingBuffer of the first sink writing to a RingBuffer, or nil.
+// Mount it on a router to serve the recent entries.
+func (l *Logger) RingBuffer() *RingBuffer {
+	for _, sink := range l.sinks {
+		if rb, ok := sink.Writer.(*RingBuffer); ok {
+			return rb
+		}
+	}
+	return nil
+}
+
+// Close closes the writers of the sinks, which are io.Closers (like the files and the syslog connections).
+func (l *Logger) Close() error {
+	return closeSinks(l.sinks)
+}
+
+// closeSinks closes the writers of the sinks, which are io.Closers.
+func closeSinks(sinks []*Sink) error {
+	var errs []string
+	for _, sink := range sinks {
+		if closer, ok := sink.Writer.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("Failed to close log sinks: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// sinkSpec is a sink of the APP_LOG_SINKS variable.
+type sinkSpec struct {
+	name   string
+	format string
+	level  logrus.Level
+}
+
+// parseSinks parses the sinks in the "name[=format[:level]],..." format, like "stdout=text:debug,file=json,memory".
+// The format is json by default and the level is trace by default.
+func parseSinks(sinks string) ([]sinkSpec, error) {
+	specs := []sinkSpec{}
+	for _, sink := range strings.Split(sinks, ",") {
+		if strings.TrimSpace(sink) == "" {
+			continue
+		}
+		parts := strings.SplitN(sink, "=", 2)
+		spec := sinkSpec{name: strings.TrimSpace(parts[0]), format: SinkFormatJSON, level: logrus.TraceLevel}
+		if len(parts) == 2 {
+			parts = append(parts[:1], strings.SplitN(parts[1], ":", 2)...)
+		}
+		switch spec.name {
+		case SinkStdout, SinkFile, SinkSyslog, SinkMemory:
+		default:
+			return nil, errors.Errorf("Invalid log sink %s", sink)
+		}
+		if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+			spec.format = strings.TrimSpace(parts[1])
+			if spec.format != SinkFormatJSON && spec.format != SinkFormatText {
+				return nil, errors.Errorf("Invalid format of log sink %s", sink)
+			}
+		}
+		if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
+			level, err := logrus.ParseLevel(strings.TrimSpace(parts[2]))
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid level of log sink %s", sink)
+			}
+			spec.level = level
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// sinksFromConfiguration creates the sinks configured by the APP_LOG_SINKS variable and the variables of the sinks,
+// or nil if the sinks are not configured. The writers of the created sinks are closed, if any of them fails.
+func sinksFromConfiguration(serviceName string, config configGetter) ([]*Sink, error) {
+	specs, err := parseSinks(config.Get(constants.APP_LOG_SINKS))
+	if err != nil || len(specs) == 0 {
+		return nil, err
+	}
+
+	sinks := []*Sink{}
+	for _, spec := range specs {
+		writer, err := sinkWriter(spec.name, serviceName, config)
+		if err != nil {
+			_ = closeSinks(sinks)
+			return nil, err
+		}
+		var formatter logrus.Formatter = BasicJSONFormatter
+		if spec.format == SinkFormatText {
+			formatter = BasicTextFormatter
+		}
+		sinks = append(sinks, NewSink(spec.name, spec.level, formatter, writer))
+	}
+	return sinks, nil
+}
+
+// sinkWriter creates the writer of the named sink.
+func sinkWriter(name, serviceName string, config configGetter) (io.Writer, error) {
+	switch name {
+	case SinkFile:
+		maxSize := configInt(config, constants.APP_LOG_FILE_MAX_SIZE, 100)
+		maxBackups := configInt(config, constants.APP_LOG_FILE_MAX_BACKUPS, 3)
+		return NewRotatingFile(config.Get(constants.APP_LOG_FILE_PATH), int64(maxSize)*1024*1024, maxBackups)
+	case SinkSyslog:
+		network := ""
+		address := config.Get(constants.APP_LOG_SYSLOG_ADDRESS)
+		if address != "" {
+			network = "udp"
+		}
+		return newSyslogWriter(network, address, serviceName)
+	case SinkMemory:
+		return NewRingBuffer(configInt(config, constants.APP_LOG_MEMORY_SIZE, 1000)), nil
+	}
+	return os.Stdout, nil
+}
+
+// configInt returns the positive integer value of the variable, or the default value.
+func configInt(config configGetter, name string, defaultValue int) int {
+	value, err := strconv.Atoi(config.Get(name))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	config "github.com/toolboxconfig"
+	constants "github.com/toolboxconstants"
+)
+
+func (ls *LoggerSuite) TestSinks() {
+	l := logrus.New()
+	l.SetLevel(logrus.DebugLevel)
+	testLogger := NewLogger(l, logrus.Fields{"service": "test-service"})
+	text := &bytes.Buffer{}
+	json := &bytes.Buffer{}
+	testLogger.SetSinks(
+		NewSink(SinkStdout, logrus.DebugLevel, BasicTextFormatter, text),
+		NewSink(SinkFile, logrus.WarnLevel, BasicJSONFormatter, json),
+	)
+	testLogger.SetSampler(NewSampler(SamplingPolicy{Initial: 1}, nil))
+
+	testLogger.NewComponentLogger("test").Entry().Debug("Debug msg")
+	testLogger.Entry().Warn("Warn msg")
+	testLogger.Entry().Warn("Warn msg")
+	ls.Contains(text.String(), `level=debug msg="Debug msg" component=test service=test-service`)
+	ls.Equal(1, strings.Count(text.String(), "Warn msg"), "The sampled entries should not reach the sinks")
+	ls.NotContains(json.String(), "Debug msg", "The entries above the level of the sink should be dropped")
+	ls.Contains(json.String(), `"level":"warning","msg":"Warn msg","service":"test-service"`)
+	ls.Equal(SinkFile, testLogger.NewComponentLogger("other").Sink(SinkFile).Name, "Child loggers should share the sinks")
+	ls.Nil(testLogger.RingBuffer())
+	ls.NoError(testLogger.Close())
+}
+
+func (ls *LoggerSuite) TestRotatingFile() {
+	path := filepath.Join(ls.T().TempDir(), "app.log")
+	rf, err := NewRotatingFile(path, 10, 2)
+	ls.NoError(err, "File should have been opened")
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		_, err = rf.Write([]byte(line))
+		ls.NoError(err, "Line should have been written")
+	}
+	ls.NoError(rf.Close())
+
+	for file, content := range map[string]string{path: "line 4\n", path + ".1": "line 3\n", path + ".2": "line 2\n"} {
+		data, err := ioutil.ReadFile(file)
+		ls.NoError(err, "File should exist")
+		ls.Equal(content, string(data))
+	}
+	_, err = os.Stat(path + ".3")
+	ls.True(os.IsNotExist(err), "Only the newest backups should be kept")
+
+	_, err = NewRotatingFile("", 10, 2)
+	ls.Error(err, "Empty path should be rejected")
+}
+
+func (ls *LoggerSuite) TestRotatingFileRenameFailure() {
+	path := filepath.Join(ls.T().TempDir(), "app.log")
+	// the backup is a non-empty directory, so the file cannot be renamed to it
+	ls.NoError(os.MkdirAll(filepath.Join(path+".1", "keep"), 0755))
+	rf, err := NewRotatingFile(path, 10, 1)
+	ls.NoError(err, "File should have been opened")
+
+	_, err = rf.Write([]byte("line 1\n"))
+	ls.NoError(err, "Line should have been written")
+	n, err := rf.Write([]byte("line 2\n"))
+	ls.Error(err, "The failed rotation should be reported")
+	ls.Contains(err.Error(), "Failed to rotate log file "+path)
+	ls.Equal(7, n, "Line should have been written after the failed rotation")
+	n, _ = rf.Write([]byte("line 3\n"))
+	ls.Equal(7, n, "The file should stay writable")
+	ls.NoError(rf.Close())
+
+	data, err := ioutil.ReadFile(path)
+	ls.NoError(err, "File should exist")
+	ls.Equal("line 1\nline 2\nline 3\n", string(data))
+}
+
+func (ls *LoggerSuite) TestRingBuffer() {
+	rb := NewRingBuffer(2)
+	for _, entry := range []string{"entry 1\n", "entry 2\n", "entry 3\n"} {
+		_, _ = rb.Write([]byte(entry))
+	}
+	router := mux.NewRouter()
+	rb.Mount(router, "/logs")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/logs", nil))
+	ls.Equal(http.StatusOK, rr.Code)
+	ls.Equal("entry 2\nentry 3\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/logs?limit=1", nil))
+	ls.Equal("entry 3\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/logs?limit=all", nil))
+	ls.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (ls *LoggerSuite) TestSinksFromConfiguration() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	ls.NoError(err, "UDP listener should have been started")
+	defer conn.Close()
+
+	path := filepath.Join(ls.T().TempDir(), "app.log")
+	conf := config.NewConfig(map[string]*config.Variable{
+		constants.APP_LOG_LEVEL:          {DefaultValue: "debug"},
+		constants.APP_LOG_SINKS:          {DefaultValue: "file=json:info, syslog=:warn, memory=text"},
+		constants.APP_LOG_FILE_PATH:      {DefaultValue: path},
+		constants.APP_LOG_SYSLOG_ADDRESS: {DefaultValue: conn.LocalAddr().String()},
+		constants.APP_LOG_MEMORY_SIZE:    {DefaultValue: "10"},
+	})
+	ls.NoError(conf.Setup(), "Configuration should have been set up")
+
+	commonLog := NewCommonLoggerFromConfiguration("test-service", "v1.2.3", conf)
+	defer commonLog.Close()
+	ls.Len(commonLog.Sinks(), 3)
+	ls.Equal(logrus.InfoLevel, commonLog.Sink(SinkFile).Level)
+	ls.Equal(BasicTextFormatter, commonLog.Sink(SinkMemory).Formatter)
+
+	commonLog.Entry().Debug("Debug msg")
+	commonLog.Entry().Error("Error msg")
+
+	data, err := ioutil.ReadFile(path)
+	ls.NoError(err, "Log file should exist")
+	ls.NotContains(string(data), "Debug msg")
+	ls.Contains(string(data), `"msg":"Error msg"`)
+	ls.Len(commonLog.RingBuffer().Entries(0), 2)
+
+	buf := make([]byte, 1024)
+	ls.NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	ls.NoError(err, "Syslog message should have been received")
+	ls.True(strings.HasPrefix(string(buf[:n]), "<11>"), "The severity should be error")
+	ls.Contains(string(buf[:n]), `"msg":"Error msg"`)
+
+	_, err = parseSinks("kafka")
+	ls.Error(err, "Unknown sinks should not be parsed")
+	_, err = parseSinks("stdout=xml")
+	ls.Error(err, "Unknown formats should not be parsed")
+	_, err = parseSinks("stdout=json:loud")
+	ls.Error(err, "Unknown levels should not be parsed")
+}
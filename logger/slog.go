@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The slog levels of the logrus levels, which have no slog level
+const (
+	LevelTrace = slog.LevelDebug - 4
+	LevelFatal = slog.LevelError + 4
+	LevelPanic = slog.LevelError + 8
+)
+
+// slogLevel returns the slog level of the logrus level.
+func slogLevel(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.PanicLevel:
+		return LevelPanic
+	case logrus.FatalLevel:
+		return LevelFatal
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	}
+	return LevelTrace
+}
+
+// logrusLevel returns the logrus level of the slog level.
+func logrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level >= LevelPanic:
+		return logrus.PanicLevel
+	case level >= LevelFatal:
+		return logrus.FatalLevel
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	case level >= slog.LevelDebug:
+		return logrus.DebugLevel
+	}
+	return logrus.TraceLevel
+}
+
+// HandlerOptions returns the slog.HandlerOptions of the handlers created by NewCommonLoggerFromConfiguration.
+// The levels are rendered with their logrus names and the time in RFC3339, like the BasicJSONFormatter does.
+// Every level is enabled, the entries are filtered by the level of the Logger.
+func HandlerOptions(addSource bool) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		AddSource: addSource,
+		Level:     LevelTrace,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.LevelKey:
+				if level, ok := a.Value.Any().(slog.Level); ok {
+					a.Value = slog.StringValue(logrusLevel(level).String())
+				}
+			case slog.TimeKey:
+				if a.Value.Kind() == slog.KindTime {
+					a.Value = slog.StringValue(a.Value.Time().Format(time.RFC3339))
+				}
+			}
+			return a
+		},
+	}
+}
+
+// handleEntry passes the entry to the slog.Handler as a record, with its fields as attributes in the order
+// of their keys.
+func handleEntry(handler slog.Handler, entry *logrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	level := slogLevel(entry.Level)
+	if !handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	var pc uintptr
+	if entry.Caller != nil {
+		// the frame holds the address of the call, slog expects the return address like runtime.Callers returns
+		pc = entry.Caller.PC + 1
+	}
+	record := slog.NewRecord(entry.Time, level, entry.Message, pc)
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		record.AddAttrs(slog.Any(key, entry.Data[key]))
+	}
+	return handler.Handle(ctx, record)
+}
+
+// NewSlogLogger creates a new logger instance backed by the slog.Handler with the default fields.
+// The records of the slog API (Slog, Handler) and the entries created by Entry, WithField, WithFields and
+// WithError are passed to the slog.Handler (and to the sinks set by SetSinks), after the level filtering,
+// the sampling and the redaction. The logrus.Logger behind the Logger only adapts the entries to records,
+// its hooks receive the entries, but not the records of the slog API. The level of the logger is the most
+// verbose level enabled by the slog.Handler.
+func NewSlogLogger(handler slog.Handler, defaultFields logrus.Fields) *Logger {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	for _, level := range logrus.AllLevels {
+		if handler.Enabled(context.Background(), slogLevel(level)) {
+			log.SetLevel(level)
+		}
+	}
+
+	l := NewLogger(log, defaultFields)
+	l.core = newPipeline(log, handler)
+	return l
+}
+
+// NewLogrusEntry creates a logrus.Entry writing to the slog.Handler, for the consumers of logrus entries
+// (like sftp.SSH.UploadToRemote) in the services using log/slog.
+func NewLogrusEntry(handler slog.Handler) *logrus.Entry {
+	return NewSlogLogger(handler, logrus.Fields{}).Entry()
+}
+
+// Handler returns the slog.Handler backing the Logger (created by NewSlogLogger or by
+// NewCommonLoggerFromConfiguration) with its default fields, so its records are filtered, sampled and redacted
+// like the entries of the Logger, and written to the slog.Handler and the sinks of the Logger.
+// The groups are prefixes of the field names. It returns nil for a logrus.FieldLogger supplied to NewLogger.
+func (l *Logger) Handler() slog.Handler {
+	if l.core == nil {
+		return nil
+	}
+	return &coreHandler{core: l.core, fields: withAttrs(l.defaultFields, "", nil)}
+}
+
+// Slog returns a slog.Logger writing through the Logger with its default fields, so its records are
+// filtered, sampled and redacted like the entries of the Logger. The groups are prefixes of the field names.
+// The slog.Logger of a logrus.FieldLogger supplied to NewLogger writes to the logrus.FieldLogger, so its hooks
+// receive the records too.
+func (l *Logger) Slog() *slog.Logger {
+	if handler := l.Handler(); handler != nil {
+		return slog.New(handler)
+	}
+	return slog.New(&logrusHandler{logger: l, fields: logrus.Fields{}})
+}
+
+// logrusHandler is a slog.Handler writing the records to the logrus.FieldLogger of a Logger created by NewLogger.
+type logrusHandler struct {
+	logger *Logger
+	fields logrus.Fields
+	prefix string
+}
+
+// Enabled implements the slog.Handler interface, it reports if the level is enabled by the logrus.Logger.
+func (h *logrusHandler) Enabled(_ context.Context, level slog.Level) bool {
+	base := baseLogger(h.logger.log)
+	return base == nil || base.IsLevelEnabled(logrusLevel(level))
+}
+
+// Handle implements the slog.Handler interface. The records above error level are logged as errors,
+// so the slog.Logger never panics or exits.
+func (h *logrusHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := h.withAttrs(nil)
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.prefix, a)
+		return true
+	})
+	level := logrusLevel(record.Level)
+	if level < logrus.ErrorLevel {
+		level = logrus.ErrorLevel
+	}
+	h.logger.WithFields(fields).WithContext(ctx).WithTime(record.Time).Log(level, record.Message)
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *logrusHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logrusHandler{logger: h.logger, fields: h.withAttrs(attrs), prefix: h.prefix}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *logrusHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &logrusHandler{logger: h.logger, fields: h.withAttrs(nil), prefix: h.prefix + name + "."}
+}
+
+// withAttrs returns a copy of the fields of the handler with the attributes.
+func (h *logrusHandler) withAttrs(attrs []slog.Attr) logrus.Fields {
+	return withAttrs(h.fields, h.prefix, attrs)
+}
+
+// withAttrs returns a copy of the fields with the attributes in the group of the prefix.
+func withAttrs(fields logrus.Fields, prefix string, attrs []slog.Attr) logrus.Fields {
+	copied := make(logrus.Fields, len(fields)+len(attrs))
+	for key, value := range fields {
+		copied[key] = value
+	}
+	for _, a := range attrs {
+		addAttr(copied, prefix, a)
+	}
+	return copied
+}
+
+// addAttr adds the attribute to the fields, the attributes of the groups with the "group." prefix.
+func addAttr(fields logrus.Fields, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, groupAttr := range value.Group() {
+			addAttr(fields, prefix, groupAttr)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[prefix+a.Key] = value.Any()
+}
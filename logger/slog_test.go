@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func (ls *LoggerSuite) TestSlogLogger() {
+	out := &bytes.Buffer{}
+	testLogger := NewSlogLogger(slog.NewJSONHandler(out, HandlerOptions(false)), logrus.Fields{"service": "test-service"})
+	ls.NotNil(testLogger.Handler(), "Logger should be backed by the handler")
+	ls.Equal(logrus.TraceLevel, testLogger.log.(*logrus.Logger).GetLevel())
+
+	testLogger.NewComponentLogger("test").WithError(errors.New("Something went wrong")).Warn("Warn msg")
+	entry := map[string]interface{}{}
+	ls.NoError(json.Unmarshal(out.Bytes(), &entry), "Entry should be JSON")
+	_, err := time.Parse(time.RFC3339, entry["time"].(string))
+	ls.NoError(err, "Time should be RFC3339")
+	ls.Equal("warning", entry["level"])
+	ls.Equal("Warn msg", entry["msg"])
+	ls.Equal("test-service", entry["service"])
+	ls.Equal("test", entry["component"])
+	ls.Equal("Something went wrong", entry[ErrorMessageField])
+
+	out.Reset()
+	warnLogger := NewSlogLogger(slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelWarn}), nil)
+	ls.Equal(logrus.WarnLevel, warnLogger.log.(*logrus.Logger).GetLevel(), "Level should be the level of the handler")
+	warnLogger.Entry().Info("Info msg")
+	NewLogrusEntry(warnLogger.Handler()).WithField("file", "report.csv").Error("Upload failed")
+	ls.NotContains(out.String(), "Info msg")
+	ls.Contains(out.String(), `level=ERROR msg="Upload failed" file=report.csv`)
+}
+
+func (ls *LoggerSuite) TestSlogLoggerSinks() {
+	out := &bytes.Buffer{}
+	testLogger := NewSlogLogger(slog.NewJSONHandler(out, HandlerOptions(false)), nil)
+	text := &bytes.Buffer{}
+	memory := NewRingBuffer(10)
+	testLogger.SetSinks(
+		NewSink(SinkStdout, logrus.InfoLevel, BasicTextFormatter, text),
+		NewSink(SinkMemory, logrus.InfoLevel, BasicJSONFormatter, memory),
+	)
+	testLogger.SetSampler(NewSampler(SamplingPolicy{Initial: 1}, nil))
+
+	testLogger.Entry().Info("Info msg")
+	testLogger.Entry().Info("Info msg")
+	ls.Equal(1, strings.Count(out.String(), `"msg":"Info msg"`), "The handler should receive the sampled entries")
+	ls.Equal(1, strings.Count(text.String(), `msg="Info msg"`), "The sinks should receive the sampled entries")
+	ls.Len(memory.Entries(0), 1)
+
+	testLogger.SetSinks()
+	testLogger.Entry().Warn("Warn msg")
+	ls.Contains(out.String(), `"msg":"Warn msg"`, "The handler should be kept without sinks")
+	ls.NotContains(text.String(), "Warn msg")
+}
+
+func (ls *LoggerSuite) TestSlogLoggerSource() {
+	out := &bytes.Buffer{}
+	testLogger := NewSlogLogger(slog.NewJSONHandler(out, HandlerOptions(true)), nil)
+	testLogger.log.(*logrus.Logger).SetReportCaller(true)
+
+	testLogger.Entry().Info("Info msg")
+	entry := map[string]interface{}{}
+	ls.NoError(json.Unmarshal(out.Bytes(), &entry), "Entry should be JSON")
+	ls.Contains(entry["source"], "function", "Source should have been added")
+	ls.Contains(entry["source"].(map[string]interface{})["file"], "slog_test.go")
+}
+
+func (ls *LoggerSuite) TestSlog() {
+	nullLogger, hook := logrusTest.NewNullLogger()
+	nullLogger.SetLevel(logrus.InfoLevel)
+	testLogger := NewLogger(nullLogger, logrus.Fields{"service": "test-service"})
+	testLogger.SetRedactor(NewDefaultRedactor())
+	log := testLogger.NewComponentLogger("test").Slog()
+
+	log.Debug("Debug msg")
+	ls.Nil(hook.LastEntry(), "Debug records should be dropped by the level of the logger")
+
+	log.With("user", "user-1").WithGroup("request").InfoContext(context.TODO(), "Request",
+		"id", 42, slog.Group("header", "authorization", "Bearer token"))
+	ls.Equal(logrus.InfoLevel, hook.LastEntry().Level)
+	ls.Equal("Request", hook.LastEntry().Message)
+	ls.Equal(logrus.Fields{
+		"service":                      "test-service",
+		"component":                    "test",
+		"user":                         "user-1",
+		"request.id":                   int64(42),
+		"request.header.authorization": "Bearer token",
+	}, hook.LastEntry().Data)
+
+	log.Log(context.TODO(), LevelPanic, "Panic msg", "password", "hunter2")
+	ls.Equal(logrus.ErrorLevel, hook.LastEntry().Level, "Records above error should be logged as errors")
+	ls.Equal(RedactedValue, hook.LastEntry().Data["password"], "Records should have been redacted")
+}
+
+func (ls *LoggerSuite) TestSlogCore() {
+	out := &bytes.Buffer{}
+	testLogger := NewSlogLogger(slog.NewJSONHandler(out, HandlerOptions(false)), logrus.Fields{"service": "test-service"})
+	testLogger.SetRedactor(NewDefaultRedactor())
+	levels, err := NewLevelController(testLogger)
+	ls.NoError(err, "Level controller should have been created")
+	levels.SetLevel("", logrus.InfoLevel, 0)
+	levels.SetLevel("db", logrus.DebugLevel, 0)
+	memory := NewRingBuffer(10)
+	testLogger.SetSinks(NewSink(SinkMemory, logrus.TraceLevel, BasicJSONFormatter, memory))
+	hook := &logrusTest.Hook{}
+	testLogger.AddHook(hook)
+
+	log := testLogger.NewComponentLogger("api").Slog()
+	log.Debug("Debug msg")
+	log.Info("Login", "password", "hunter2")
+	testLogger.NewComponentLogger("db").Slog().Debug("Query")
+	ls.Empty(hook.AllEntries(), "The records of the slog API should not pass through the logrus adapter")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	ls.Len(lines, 2, "The debug record of the api component should have been dropped")
+	entry := map[string]interface{}{}
+	ls.NoError(json.Unmarshal([]byte(lines[0]), &entry), "Entry should be JSON")
+	ls.Equal("Login", entry["msg"])
+	ls.Equal("test-service", entry["service"])
+	ls.Equal("api", entry["component"])
+	ls.Equal(RedactedValue, entry["password"], "The records of the slog API should have been redacted")
+	ls.Contains(lines[1], `"msg":"Query"`)
+	ls.Len(memory.Entries(0), 2, "The sinks should receive the records of the slog API")
+
+	testLogger.Entry().Info("Entry msg")
+	ls.Len(hook.AllEntries(), 1, "The entries should pass through the logrus adapter")
+	ls.Contains(out.String(), `"msg":"Entry msg"`)
+}
@@ -0,0 +1,67 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"io"
+	"log/syslog"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SyslogWriter is a LevelWriter sending the entries to syslog, with the severity of their level.
+type SyslogWriter struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogWriter connects to the syslog daemon at the address with the network (like "udp"),
+// or to the local syslog for an empty network. The tag is usually the name of the service.
+func NewSyslogWriter(network, address, tag string) (*SyslogWriter, error) {
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to connect to syslog %s", address)
+	}
+	return &SyslogWriter{writer: writer}, nil
+}
+
+// newSyslogWriter creates the writer of the syslog sink.
+func newSyslogWriter(network, address, tag string) (io.Writer, error) {
+	writer, err := NewSyslogWriter(network, address, tag)
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// Write implements the io.Writer interface, the entries are sent with info severity.
+func (sw *SyslogWriter) Write(p []byte) (int, error) {
+	return sw.writer.Write(p)
+}
+
+// WriteLevel implements the LevelWriter interface, the entries are sent with the severity of their level.
+func (sw *SyslogWriter) WriteLevel(level logrus.Level, p []byte) (int, error) {
+	var err error
+	msg := string(p)
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		err = sw.writer.Crit(msg)
+	case logrus.ErrorLevel:
+		err = sw.writer.Err(msg)
+	case logrus.WarnLevel:
+		err = sw.writer.Warning(msg)
+	case logrus.InfoLevel:
+		err = sw.writer.Info(msg)
+	default:
+		err = sw.writer.Debug(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close implements the io.Closer interface.
+func (sw *SyslogWriter) Close() error {
+	return sw.writer.Close()
+}
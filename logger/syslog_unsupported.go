@@ -0,0 +1,15 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"io"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// newSyslogWriter fails, as log/syslog is not available on the platform.
+func newSyslogWriter(network, address, tag string) (io.Writer, error) {
+	return nil, errors.Errorf("The %s sink is not supported on %s", SinkSyslog, runtime.GOOS)
+}
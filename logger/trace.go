@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fields of the OpenTelemetry span correlation added by ForContext, FromContext and the gorm logger.
+const (
+	TraceIDField    = "trace_id"
+	SpanIDField     = "span_id"
+	TraceFlagsField = "trace_flags"
+)
+
+// traceFields returns the ids and the sampling flags of the OpenTelemetry span carried by the context,
+// or no fields if the context carries no valid span.
+func traceFields(ctx context.Context) logrus.Fields {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logrus.Fields{}
+	}
+	return logrus.Fields{
+		TraceIDField:    spanContext.TraceID().String(),
+		SpanIDField:     spanContext.SpanID().String(),
+		TraceFlagsField: spanContext.TraceFlags().String(),
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func (ls *LoggerSuite) TestTraceCorrelation() {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() { ls.NoError(provider.Shutdown(context.Background())) }()
+
+	nullLogger, hook := logrusTest.NewNullLogger()
+	testLogger := NewLogger(nullLogger, nil)
+
+	ctx, span := provider.Tracer("logger-test").Start(testLogger.WithContext(context.Background()), "test-span")
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+
+	FromContext(ctx).Entry().Info("Info msg")
+	ls.Equal(traceID, hook.LastEntry().Data[TraceIDField], "The trace id should have been added to the log entry")
+	ls.Equal(spanID, hook.LastEntry().Data[SpanIDField], "The span id should have been added to the log entry")
+	ls.Equal("01", hook.LastEntry().Data[TraceFlagsField], "The span should have been sampled")
+
+	gormLog := testLogger.NewGormLogger("GORM")
+	gormLog.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	ls.Equal("SELECT 1", hook.LastEntry().Data[GormSQLField], "The SQL statement should have been logged")
+	ls.Equal(traceID, hook.LastEntry().Data[TraceIDField], "The trace id should have been added to the SQL entry")
+	ls.Equal(spanID, hook.LastEntry().Data[SpanIDField], "The span id should have been added to the SQL entry")
+	ls.Equal("GORM", hook.LastEntry().Data["component"])
+
+	span.End()
+	ls.Len(exporter.GetSpans(), 1, "The span should have been exported")
+
+	testLogger.ForContext(context.Background()).Entry().Info("Info msg")
+	ls.NotContains(hook.LastEntry().Data, TraceIDField, "No trace id should be added without span")
+}
@@ -43,6 +43,7 @@ func (rw *responseWriter) WriteHeader(code int) {
 
 // LoggingMiddleware logs the incoming HTTP request & its duration onto the supplied logger when the response is sent.
 // this middleware also recovers from any panic in the middleware chain, and turns it into an error log entry
+// the logger is attached to the request context, so the handlers can retrieve it with logger.FromContext
 func LoggingMiddleware(logger *logger.Logger) func(http.Handler) http.Handler {
 
 	return func(next http.Handler) http.Handler {
@@ -59,8 +60,8 @@ func LoggingMiddleware(logger *logger.Logger) func(http.Handler) http.Handler {
 			}()
 			// wrap the original ResponseWriter
 			wrapped := wrapResponseWriter(w)
-			// call next middleware
-			next.ServeHTTP(wrapped, r)
+			// call next middleware with the logger attached to the request context
+			next.ServeHTTP(wrapped, r.WithContext(logger.WithContext(r.Context())))
 			// when the call to next returns, we log out the request and the elapsed time
 			fields := logrus.Fields{
 				"status":   wrapped.status,
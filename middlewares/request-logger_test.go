@@ -168,6 +168,23 @@ func (rls *RequestLoggerSuite) TestRequestLogger() {
 
 }
 
+// TestLoggerInContext checks that the handlers can retrieve the logger from the request context
+func (rls *RequestLoggerSuite) TestLoggerInContext() {
+	logger, hook := test.NewNullLogger()
+	appLogger := toolLog.NewLogger(logger, logrus.Fields{"service": "test-service"})
+
+	handler := LoggingMiddleware(appLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		toolLog.FromContext(r.Context()).Entry().Info("Handler")
+	}))
+	req, err := http.NewRequest("GET", "/", nil)
+	rls.NoError(err, "Test request should be created")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	rls.Equal(2, len(hook.Entries), "There should be a handler and a request log entry")
+	rls.Equal("Handler", hook.Entries[0].Message)
+	rls.Equal("test-service", hook.Entries[0].Data["service"], "The handler should have used the attached logger")
+}
+
 // TestRequestLogger runs the suite
 func Test_RequestLogger(t *testing.T) {
 	suite.Run(t, new(RequestLoggerSuite))
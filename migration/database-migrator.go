@@ -1,6 +1,7 @@
 package migration
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
@@ -59,6 +60,13 @@ func (mig *DatabaseMigrator) getSource() *migrate.HttpFileSystemMigrationSource
 // Migrate tries to execute the migration. It returns the number of actually applied migration steps,
 // and an optional database error.
 func (mig *DatabaseMigrator) Migrate(direction Direction, steps int) (int, error) {
+	return mig.MigrateContext(context.Background(), direction, steps)
+}
+
+// MigrateContext executes the migration like Migrate, but one step at a time, so it stops between the steps
+// (never in the middle of a migration script) if the context is cancelled. It returns the number of the applied
+// steps, and the error of the context if the migration was stopped.
+func (mig *DatabaseMigrator) MigrateContext(ctx context.Context, direction Direction, steps int) (int, error) {
 	db, err := mig.getDB()
 	if err != nil {
 		return 0, errors.Wrap(err, "Failed to set up migration")
@@ -73,17 +81,29 @@ func (mig *DatabaseMigrator) Migrate(direction Direction, steps int) (int, error
 
 	migrate.SetSchema(mig.databaseSchema)
 
-	appliedSteps, err := migrate.ExecMax(
-		db,
-		mig.databaseDriver,
-		source,
-		migrate.MigrationDirection(direction),
-		steps,
-	)
-	if err != nil {
-		return appliedSteps, errors.Wrap(err, "Failed to execute migration")
+	// Without a context which can be cancelled, the steps are executed at once
+	if ctx.Done() == nil {
+		appliedSteps, err := migrate.ExecMax(db, mig.databaseDriver, source, migrate.MigrationDirection(direction), steps)
+		if err != nil {
+			return appliedSteps, errors.Wrap(err, "Failed to execute migration")
+		}
+		return appliedSteps, nil
 	}
 
+	appliedSteps := 0
+	for steps <= 0 || appliedSteps < steps {
+		if err := ctx.Err(); err != nil {
+			return appliedSteps, errors.Wrapf(err, "Migration stopped after %d step(s)", appliedSteps)
+		}
+		applied, err := migrate.ExecMax(db, mig.databaseDriver, source, migrate.MigrationDirection(direction), 1)
+		appliedSteps += applied
+		if err != nil {
+			return appliedSteps, errors.Wrap(err, "Failed to execute migration")
+		}
+		if applied == 0 {
+			break
+		}
+	}
 	return appliedSteps, nil
 }
 
@@ -1,6 +1,7 @@
 package migration
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -23,6 +24,13 @@ type Migrator interface {
 	GetMigrationInfo() (*models.MigrationInfo, error)
 }
 
+// ContextMigrator is a Migrator, which stops the migration between the steps if the context is cancelled.
+// The MigratorCLI uses it to stop the migrations on SIGINT or SIGTERM (see cli.Command.ExecuteContext).
+type ContextMigrator interface {
+	Migrator
+	MigrateContext(ctx context.Context, direction Direction, steps int) (int, error)
+}
+
 // MigratorCLI is a wrapper used to generate a CLI interface for a Migrator in the context of a Services.
 type MigratorCLI struct {
 	migrator  Migrator
@@ -51,7 +59,13 @@ func (mcli *MigratorCLI) BuildMigrationCommand() *cli.Command {
 
 	migrationCommand := cli.NewCommand("migration").
 		WithAliases("migrate", "migrator").
-		WithTask(cli.EndWithMessage(mcli.migrationUsage())).
+		WithShort("Manage SQL database migrations").
+		WithLong(fmt.Sprintf(`This utility command is used to execute SQL migration scripts against the database. A separate table
+called gorp_migrations is created in the database, to keep track of the allready applied migrations.
+
+Migration script directory: %s`, mcli.scriptDir)).
+		WithExamples("./app migrate info").
+		WithRun(cli.EndWithHelp).
 		WithSubCommands(
 			mcli.migrateInfoCommand(),
 			mcli.migrateGenerateCommand(),
@@ -67,6 +81,7 @@ func (mcli *MigratorCLI) BuildMigrationCommand() *cli.Command {
 func (mcli *MigratorCLI) migrateInfoCommand() *cli.Command {
 	return cli.NewCommand("info").
 		WithAliases("status").
+		WithShort("Prints the available migration scripts and the time they were applied").
 		WithTask(func(args []string) error {
 			info, err := mcli.migrator.GetMigrationInfo()
 			if err != nil {
@@ -80,6 +95,8 @@ func (mcli *MigratorCLI) migrateInfoCommand() *cli.Command {
 func (mcli *MigratorCLI) migrateGenerateCommand() *cli.Command {
 	return cli.NewCommand("generate").
 		WithAliases("gen", "new").
+		WithShort("Generate one or more new migration script files").
+		WithExamples("./app migrate generate create-users-table").
 		WithTask(func(args []string) error {
 			if len(args) == 0 {
 				return errors.New("generate needs at least one script-name as an argument")
@@ -100,71 +117,63 @@ func (mcli *MigratorCLI) migrateGenerateCommand() *cli.Command {
 }
 
 func (mcli *MigratorCLI) migrateUpallCommand() *cli.Command {
-	return cli.NewCommand("upall").WithTask(func(args []string) error {
-		res, err := mcli.migrator.Migrate(Up, 0)
-		if err != nil {
-			return errors.Wrap(err, "Failed to migrate Up all")
-		}
-		mcli.logger.WithField("Steps taken", res).Info("Migrate upall succeeded")
-		return nil
-	})
+	return cli.NewCommand("upall").
+		WithShort("Migrate database all the way up").
+		WithContextTask(func(ctx context.Context, args []string) error {
+			res, err := mcli.migrate(ctx, Up, 0)
+			if err != nil {
+				return errors.Wrap(err, "Failed to migrate Up all")
+			}
+			mcli.logger.WithField("Steps taken", res).Info("Migrate upall succeeded")
+			return nil
+		})
 }
 
 func (mcli *MigratorCLI) migrateUpCommand() *cli.Command {
-	return cli.NewCommand("up").WithTask(func(args []string) error {
-		res, err := mcli.migrator.Migrate(Up, 1)
-		if err != nil {
-			return errors.Wrap(err, "Failed to migrate Up")
-		}
-		mcli.logger.WithField("Steps taken", res).Info("Migrate up succeeded")
-		return nil
-	})
+	return cli.NewCommand("up").
+		WithShort("Migrate database one step up").
+		WithContextTask(func(ctx context.Context, args []string) error {
+			res, err := mcli.migrate(ctx, Up, 1)
+			if err != nil {
+				return errors.Wrap(err, "Failed to migrate Up")
+			}
+			mcli.logger.WithField("Steps taken", res).Info("Migrate up succeeded")
+			return nil
+		})
 }
 
 func (mcli *MigratorCLI) migrateDownCommand() *cli.Command {
-	return cli.NewCommand("down").WithTask(func(args []string) error {
-		res, err := mcli.migrator.Migrate(Down, 1)
-		if err != nil {
-			return errors.Wrap(err, "Failed to migrate Down")
-		}
-		mcli.logger.WithField("Steps taken", res).Info("Migrate down succeeded")
-		return nil
-	})
+	return cli.NewCommand("down").
+		WithShort("Migrate database one step down").
+		WithContextTask(func(ctx context.Context, args []string) error {
+			res, err := mcli.migrate(ctx, Down, 1)
+			if err != nil {
+				return errors.Wrap(err, "Failed to migrate Down")
+			}
+			mcli.logger.WithField("Steps taken", res).Info("Migrate down succeeded")
+			return nil
+		})
 }
 
 func (mcli *MigratorCLI) migrateResetCommand() *cli.Command {
-	return cli.NewCommand("reset").WithTask(func(args []string) error {
-		res, err := mcli.migrator.Migrate(Down, 0)
-		if err != nil {
-			return errors.Wrap(err, "Failed to reset migrations")
-		}
-		mcli.logger.WithField("Steps taken", res).Info("Migrate reset succeeded")
-		return nil
-	})
+	return cli.NewCommand("reset").
+		WithShort("Migrate database all the way down").
+		WithContextTask(func(ctx context.Context, args []string) error {
+			res, err := mcli.migrate(ctx, Down, 0)
+			if err != nil {
+				return errors.Wrap(err, "Failed to reset migrations")
+			}
+			mcli.logger.WithField("Steps taken", res).Info("Migrate reset succeeded")
+			return nil
+		})
 }
 
-// migrationUsage returns the usage string of the migration command in the Services context.
-func (mcli *MigratorCLI) migrationUsage() string {
-	return fmt.Sprintf(`
-This utility command is used to execute SQL migration scripts against the database. A separate table
-called gorp_migrations is created in the database, to keep track of the allready applied migrations.
-
-Migration script directory: %s
-
-Usage: app migrate <subcommand>
-
-Example:
-
-$ ./app migrate info
-
-Available subcommands:
-	info     - Prints the available migration scripts and the time they were applied
-	upall    - Migrate database all the way up
-	up       - Migrate database one step up
-	down     - Migrate database one step down
-	reset    - Migrate database all the way down
-	generate - Generate one or more new migration script files
-`, mcli.scriptDir)
+// migrate executes the migration with the context, if the Migrator is a ContextMigrator
+func (mcli *MigratorCLI) migrate(ctx context.Context, direction Direction, steps int) (int, error) {
+	if migrator, ok := mcli.migrator.(ContextMigrator); ok {
+		return migrator.MigrateContext(ctx, direction, steps)
+	}
+	return mcli.migrator.Migrate(direction, steps)
 }
 
 func (mcli *MigratorCLI) renderMigrationInfo(info *models.MigrationInfo) string {
@@ -2,6 +2,7 @@ package migration
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	// PostgreSQL database driver
 	_ "github.com/lib/pq"
@@ -16,6 +18,7 @@ import (
 	logrusTest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/suite"
 
+	cli "github.com/toolboxcli"
 	constants "github.com/toolboxconstants"
 	logger "github.com/toolboxlogger"
 	models "github.com/toolboxmodels"
@@ -123,6 +126,7 @@ func (mcts *MigratorCLITestSuite) TestNewMigratorCLI() {
 
 func (mcts *MigratorCLITestSuite) TestNewMigratorCLI_empty() {
 	cmd := NewMigratorCLI(MigratorCLIOptions{}).BuildMigrationCommand()
+	mcts.NoError(cmd.Validate(), "The subcommands should not share names or aliases")
 
 	out, err := mcts.execAndCapture(func() error { return cmd.Execute(nil) })
 
@@ -131,7 +135,7 @@ func (mcts *MigratorCLITestSuite) TestNewMigratorCLI_empty() {
 	for _, elem := range []string{
 		"This utility command is used to execute SQL migration scripts against the database.",
 		"$ ./app migrate info",
-		"generate - Generate one or more new migration script files",
+		"generate  Generate one or more new migration script files",
 	} {
 		mcts.Containsf(out, elem, "The output: %s should contain: %s", out, elem)
 	}
@@ -155,6 +159,44 @@ func (fm *failMigrator) GetMigrationInfo() (*models.MigrationInfo, error) {
 	return nil, errors.New("Failed to get MigrationInfo")
 }
 
+// contextMigrator is a ContextMigrator, which stops on the cancelled contexts
+type contextMigrator struct {
+	failMigrator
+}
+
+func (cm *contextMigrator) MigrateContext(ctx context.Context, direction Direction, steps int) (int, error) {
+	<-ctx.Done()
+	return 0, errors.Wrap(ctx.Err(), "Migration stopped after 0 step(s)")
+}
+
+func (mcts *MigratorCLITestSuite) TestMigratorCLI_context() {
+	nullLogger, _ := logrusTest.NewNullLogger()
+
+	cmd := NewMigratorCLI(MigratorCLIOptions{
+		Migrator: &contextMigrator{},
+		Logger:   logger.NewLogger(nullLogger, nil),
+	}).BuildMigrationCommand().WithTimeout(10 * time.Millisecond)
+
+	err := cmd.Execute([]string{"upall"})
+	mcts.EqualError(err, "migration upall timed out after 10ms: Failed to migrate Up all: Migration stopped after 0 step(s): context deadline exceeded")
+	mcts.Equal(cli.ExitCodeTimeout, cli.ExitCode(err))
+}
+
+func (mcts *MigratorCLITestSuite) TestMigratorCLI_help() {
+	nullLogger, _ := logrusTest.NewNullLogger()
+
+	cmd := NewMigratorCLI(MigratorCLIOptions{
+		Migrator: &failMigrator{},
+		Logger:   logger.NewLogger(nullLogger, nil),
+	}).BuildMigrationCommand()
+
+	for _, flag := range []string{"--help", "-h"} {
+		out, err := mcts.execAndCapture(func() error { return cmd.Execute([]string{"reset", flag}) })
+		mcts.NoError(err, "The reset should not have been executed")
+		mcts.Contains(out, "Migrate database all the way down", "The help of reset should have been printed")
+	}
+}
+
 func (mcts *MigratorCLITestSuite) TestMigratorCLI_errors() {
 	nullLogger, _ := logrusTest.NewNullLogger()
 
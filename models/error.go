@@ -11,3 +11,19 @@ type ErrorResponseFormat struct {
 	Message     string `json:"message"`
 	MessageCode string `json:"messageCode"`
 }
+
+// GetMessageCode returns the MessageCode of the error, it implements the logger.MessageCoder interface
+func (erf ErrorResponseFormat) GetMessageCode() string {
+	return erf.MessageCode
+}
+
+// AppError is the typed application error of an ErrorResponseFormat. The ErrorResponseFormat is not an error
+// itself, so it is logged with its fields (and not only with its message) by logrus.
+type AppError struct {
+	ErrorResponseFormat
+}
+
+// Error implements the error interface, it returns the Message of the ErrorResponseFormat
+func (appErr AppError) Error() string {
+	return appErr.Message
+}